@@ -143,6 +143,20 @@ type Account struct {
 	Orders []Order `json:"orders"`
 }
 
+func (a *Account) UnmarshalJSON(data []byte) error {
+	type plain Account
+	aux := struct {
+		*plain
+		Orders json.RawMessage `json:"orders"`
+	}{plain: (*plain)(a)}
+	err := json.Unmarshal(data, &aux)
+	if err != nil {
+		return err
+	}
+	a.Orders, err = unmarshalOrders(aux.Orders)
+	return err
+}
+
 type NullableTime struct {
 	*time.Time
 }
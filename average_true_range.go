@@ -0,0 +1,98 @@
+package oanda_sdk
+
+import (
+	"errors"
+	"fmt"
+	"github.com/shopspring/decimal"
+)
+
+// GetAverageTrueRange computes the average true range of an instrument over the last period complete mid
+// candlesticks of the granularity (see AverageTrueRange)
+func (c *Client) GetAverageTrueRange(instrument string, granularity CandlestickGranularity, period int) (decimal.Decimal, error) {
+	// The true range of the first candlestick needs the close of the one before it, and the last one may be incomplete
+	count := period + 2
+	response, err := c.GetInstrumentCandles(instrument, GetInstrumentCandlesRequest{
+		Granularity: &granularity,
+		Count:       &count,
+	})
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return AverageTrueRange(response.Candles, period)
+}
+
+// AverageTrueRange computes the average true range of the complete mid candlesticks: the true ranges of the
+// candlesticks after the first are averaged with a WilderAverage of the period.
+func AverageTrueRange(candles []Candlestick, period int) (decimal.Decimal, error) {
+	average, err := NewWilderAverage(period)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	var previous *CandlestickData
+	complete := 0
+	for _, candle := range candles {
+		if !candle.Complete || candle.Mid == nil {
+			continue
+		}
+		complete++
+		if previous != nil {
+			average.Add(candle.Mid.TrueRange(previous.Close))
+		}
+		previous = candle.Mid
+	}
+	atr, ok := average.Value()
+	if !ok {
+		return decimal.Zero, fmt.Errorf("%d complete mid candlesticks needed, got %d", period+1, complete)
+	}
+	return atr, nil
+}
+
+// TrueRange returns the greatest of the candlestick's range and the distances of its high and low from the close of
+// the previous candlestick
+func (cd CandlestickData) TrueRange(previousClose decimal.Decimal) decimal.Decimal {
+	return decimal.Max(
+		cd.High.Sub(cd.Low),
+		cd.High.Sub(previousClose).Abs(),
+		cd.Low.Sub(previousClose).Abs(),
+	)
+}
+
+// WilderAverage is Wilder's moving average of a period: the first period values are averaged, the following ones are
+// smoothed in with a weight of 1/period
+type WilderAverage struct {
+	period int
+	count  int
+	value  decimal.Decimal
+}
+
+// NewWilderAverage creates a Wilder's moving average of the period
+func NewWilderAverage(period int) (*WilderAverage, error) {
+	if period <= 0 {
+		return nil, errors.New("period must be positive")
+	}
+	return &WilderAverage{period: period}, nil
+}
+
+// Add adds a value and returns the average, which is only available once period values were added
+func (w *WilderAverage) Add(value decimal.Decimal) (decimal.Decimal, bool) {
+	n := decimal.NewFromInt(int64(w.period))
+	w.count++
+	switch {
+	case w.count < w.period:
+		// The sum of the values so far
+		w.value = w.value.Add(value)
+	case w.count == w.period:
+		w.value = w.value.Add(value).Div(n)
+	default:
+		w.value = w.value.Mul(n.Sub(decimal.NewFromInt(1))).Add(value).Div(n)
+	}
+	return w.Value()
+}
+
+// Value returns the current average, which is only available once period values were added
+func (w *WilderAverage) Value() (decimal.Decimal, bool) {
+	if w.count < w.period {
+		return decimal.Zero, false
+	}
+	return w.value, true
+}
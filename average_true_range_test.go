@@ -0,0 +1,35 @@
+package oanda_sdk
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestAverageTrueRange(t *testing.T) {
+	candle := func(high, low, close string) Candlestick {
+		return Candlestick{Complete: true, Mid: &CandlestickData{
+			High:  decimal.RequireFromString(high),
+			Low:   decimal.RequireFromString(low),
+			Close: decimal.RequireFromString(close),
+		}}
+	}
+	candles := []Candlestick{
+		candle("1.10", "1.00", "1.05"),
+		candle("1.08", "1.04", "1.06"), // 0.04
+		candle("1.20", "1.10", "1.15"), // gap up: 1.20 - 1.06 = 0.14
+		candle("1.16", "1.12", "1.13"), // 0.04
+		{Complete: false, Mid: &CandlestickData{High: decimal.NewFromInt(5)}},
+	}
+	atr, err := AverageTrueRange(candles, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// (0.04 + 0.14) / 2 = 0.09, smoothed with 0.04: (0.09 + 0.04) / 2 = 0.065
+	if atr.String() != "0.065" {
+		t.Error("Got ", atr)
+	}
+	if _, err := AverageTrueRange(candles, 4); err == nil {
+		t.Error("Expected an error for too few candlesticks")
+	}
+}
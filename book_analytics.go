@@ -0,0 +1,162 @@
+package oanda_sdk
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+// BookSide holds the percentages of long and short orders or positions
+type BookSide struct {
+	// The percentage of the total number of orders or positions represented by long orders or positions.
+	Long decimal.Decimal
+
+	// The percentage of the total number of orders or positions represented by short orders or positions.
+	Short decimal.Decimal
+}
+
+// Ratio returns the ratio of the long to the short percentage. The second return value is false when there are no
+// short orders or positions.
+func (bs BookSide) Ratio() (decimal.Decimal, bool) {
+	if bs.Short.IsZero() {
+		return decimal.Zero, false
+	}
+	return bs.Long.Div(bs.Short), true
+}
+
+// LongPercent returns the long orders or positions as a percentage of the long and short ones, e.g. 60 when 60% of
+// the positions are long. The second return value is false when there are no orders or positions.
+func (bs BookSide) LongPercent() (decimal.Decimal, bool) {
+	total := bs.Long.Add(bs.Short)
+	if total.IsZero() {
+		return decimal.Zero, false
+	}
+	return bs.Long.Mul(decimal.NewFromInt(100)).Div(total), true
+}
+
+// BookDistribution holds the cumulative percentages of the buckets below and above the price of a book
+type BookDistribution struct {
+	// The buckets whose mid price is below the price.
+	Below BookSide
+
+	// The buckets whose mid price is at or above the price.
+	Above BookSide
+}
+
+// Total returns the cumulative percentages of all the buckets
+func (bd BookDistribution) Total() BookSide {
+	return BookSide{Long: bd.Below.Long.Add(bd.Above.Long), Short: bd.Below.Short.Add(bd.Above.Short)}
+}
+
+// StopCluster is a range of adjacent order book buckets holding stop orders
+type StopCluster struct {
+	// The lowest price (inclusive) covered by the cluster.
+	Low decimal.Decimal
+
+	// The highest price (exclusive) covered by the cluster.
+	High decimal.Decimal
+
+	// Whether the stop orders buy (long orders above the price, e.g. the stop losses of short positions) or sell
+	// (short orders below the price, e.g. the stop losses of long positions).
+	Buy bool
+
+	// The percentage of the total number of orders represented by the stop orders of the cluster.
+	Percent decimal.Decimal
+}
+
+// bookBucket is a bucket of an order book or a position book
+type bookBucket struct {
+	price decimal.Decimal
+	side  BookSide
+}
+
+// distribution sums the buckets within the distance of the price, or all of them when the distance is nil
+func distribution(price, width decimal.Decimal, buckets []bookBucket, distance *decimal.Decimal) BookDistribution {
+	var result BookDistribution
+	half := width.Div(decimal.NewFromInt(2))
+	for _, bucket := range buckets {
+		mid := bucket.price.Add(half)
+		if distance != nil && mid.Sub(price).Abs().GreaterThan(*distance) {
+			continue
+		}
+		side := &result.Above
+		if mid.LessThan(price) {
+			side = &result.Below
+		}
+		side.Long = side.Long.Add(bucket.side.Long)
+		side.Short = side.Short.Add(bucket.side.Short)
+	}
+	return result
+}
+
+func (ob OrderBook) buckets() []bookBucket {
+	buckets := make([]bookBucket, len(ob.Buckets))
+	for i, bucket := range ob.Buckets {
+		buckets[i] = bookBucket{price: bucket.Price, side: BookSide{Long: bucket.LongCountPercent, Short: bucket.ShortCountPercent}}
+	}
+	return buckets
+}
+
+func (pb PositionBook) buckets() []bookBucket {
+	buckets := make([]bookBucket, len(pb.Buckets))
+	for i, bucket := range pb.Buckets {
+		buckets[i] = bookBucket{price: bucket.Price, side: BookSide{Long: bucket.LongCountPercent, Short: bucket.ShortCountPercent}}
+	}
+	return buckets
+}
+
+// Distribution returns the cumulative percentages of the long and short orders below and above the price
+func (ob OrderBook) Distribution() BookDistribution {
+	return distribution(ob.Price, ob.BucketWidth, ob.buckets(), nil)
+}
+
+// Around returns the cumulative percentages of the long and short orders whose bucket's mid price is within the
+// distance below and above the price
+func (ob OrderBook) Around(distance decimal.Decimal) BookDistribution {
+	return distribution(ob.Price, ob.BucketWidth, ob.buckets(), &distance)
+}
+
+// Sentiment returns the cumulative percentages of all the long and short orders
+func (ob OrderBook) Sentiment() BookSide {
+	return ob.Distribution().Total()
+}
+
+// StopClusters returns the ranges of adjacent buckets whose stop orders represent at least the minimum percentage of
+// the orders each, ordered by price like the buckets. Long orders above the price and short orders below it are taken
+// as stop orders, as limit orders would be filled there already.
+func (ob OrderBook) StopClusters(minPercent decimal.Decimal) []StopCluster {
+	var clusters []StopCluster
+	half := ob.BucketWidth.Div(decimal.NewFromInt(2))
+	for _, bucket := range ob.Buckets {
+		buy := !bucket.Price.Add(half).LessThan(ob.Price)
+		percent := bucket.ShortCountPercent
+		if buy {
+			percent = bucket.LongCountPercent
+		}
+		if percent.LessThan(minPercent) || percent.IsZero() {
+			continue
+		}
+		if n := len(clusters); n > 0 && clusters[n-1].Buy == buy && clusters[n-1].High.Equal(bucket.Price) {
+			clusters[n-1].High = bucket.Price.Add(ob.BucketWidth)
+			clusters[n-1].Percent = clusters[n-1].Percent.Add(percent)
+			continue
+		}
+		clusters = append(clusters, StopCluster{Low: bucket.Price, High: bucket.Price.Add(ob.BucketWidth), Buy: buy, Percent: percent})
+	}
+	return clusters
+}
+
+// Distribution returns the cumulative percentages of the long and short positions below and above the price
+func (pb PositionBook) Distribution() BookDistribution {
+	return distribution(pb.Price, pb.BucketWidth, pb.buckets(), nil)
+}
+
+// Around returns the cumulative percentages of the long and short positions whose bucket's mid price is within the
+// distance below and above the price
+func (pb PositionBook) Around(distance decimal.Decimal) BookDistribution {
+	return distribution(pb.Price, pb.BucketWidth, pb.buckets(), &distance)
+}
+
+// Sentiment returns the cumulative percentages of all the long and short positions, e.g. a LongPercent of 60 means
+// that 60% of the positions are long
+func (pb PositionBook) Sentiment() BookSide {
+	return pb.Distribution().Total()
+}
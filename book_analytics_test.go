@@ -0,0 +1,97 @@
+package oanda_sdk
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func orderBookBucket(price, long, short string) OrderBookBucket {
+	return OrderBookBucket{
+		Price:             decimal.RequireFromString(price),
+		LongCountPercent:  decimal.RequireFromString(long),
+		ShortCountPercent: decimal.RequireFromString(short),
+	}
+}
+
+func analyticsOrderBook() OrderBook {
+	return OrderBook{
+		Instrument:  "EUR_USD",
+		Price:       decimal.RequireFromString("1.1012"),
+		BucketWidth: decimal.RequireFromString("0.0005"),
+		Buckets: []OrderBookBucket{
+			orderBookBucket("1.0990", "0.5", "2.0"),
+			orderBookBucket("1.0995", "0.3", "1.5"),
+			orderBookBucket("1.1000", "1.0", "0.2"),
+			orderBookBucket("1.1005", "0.4", "0.1"),
+			orderBookBucket("1.1010", "0.2", "0.3"),
+			orderBookBucket("1.1015", "1.2", "0.4"),
+			orderBookBucket("1.1020", "0.1", "0.5"),
+			orderBookBucket("1.1030", "2.5", "0.2"),
+		},
+	}
+}
+
+func TestOrderBookDistribution(t *testing.T) {
+	book := analyticsOrderBook()
+	distribution := book.Distribution()
+	if !distribution.Below.Long.Equal(decimal.RequireFromString("2.2")) || !distribution.Below.Short.Equal(decimal.RequireFromString("3.8")) {
+		t.Error("Got ", distribution.Below)
+	}
+	if !distribution.Above.Long.Equal(decimal.RequireFromString("4.0")) || !distribution.Above.Short.Equal(decimal.RequireFromString("1.4")) {
+		t.Error("Got ", distribution.Above)
+	}
+
+	// The buckets from 1.1000 to 1.1015 have their mid price within 0.001 of the price
+	around := book.Around(decimal.RequireFromString("0.001"))
+	if !around.Below.Long.Equal(decimal.RequireFromString("1.4")) || !around.Above.Long.Equal(decimal.RequireFromString("1.4")) {
+		t.Error("Got ", around)
+	}
+
+	sentiment := book.Sentiment()
+	if ratio, ok := sentiment.Ratio(); !ok || !ratio.Equal(decimal.RequireFromString("6.2").Div(decimal.RequireFromString("5.2"))) {
+		t.Error("Got ", ratio, ok)
+	}
+	if percent, ok := sentiment.LongPercent(); !ok || !percent.Equal(decimal.RequireFromString("620").Div(decimal.RequireFromString("11.4"))) {
+		t.Error("Got ", percent, ok)
+	}
+	if _, ok := (BookSide{}).LongPercent(); ok {
+		t.Error("Got a percentage of an empty book")
+	}
+}
+
+func TestOrderBookStopClusters(t *testing.T) {
+	clusters := analyticsOrderBook().StopClusters(decimal.RequireFromString("1"))
+	if len(clusters) != 3 {
+		t.Fatal("Got ", clusters)
+	}
+	if clusters[0].Buy || !clusters[0].Low.Equal(decimal.RequireFromString("1.0990")) || !clusters[0].High.Equal(decimal.RequireFromString("1.1000")) || !clusters[0].Percent.Equal(decimal.RequireFromString("3.5")) {
+		t.Error("Got ", clusters[0])
+	}
+	if !clusters[1].Buy || !clusters[1].Low.Equal(decimal.RequireFromString("1.1015")) || !clusters[1].Percent.Equal(decimal.RequireFromString("1.2")) {
+		t.Error("Got ", clusters[1])
+	}
+	// The bucket at 1.1020 has too few stop orders, so 1.1030 starts a new cluster
+	if !clusters[2].Buy || !clusters[2].Low.Equal(decimal.RequireFromString("1.1030")) {
+		t.Error("Got ", clusters[2])
+	}
+}
+
+func TestPositionBookSentiment(t *testing.T) {
+	book := PositionBook{
+		Price:       decimal.RequireFromString("150.05"),
+		BucketWidth: decimal.RequireFromString("0.05"),
+		Buckets: []PositionBookBucket{
+			{Price: decimal.RequireFromString("149.95"), LongCountPercent: decimal.RequireFromString("3"), ShortCountPercent: decimal.RequireFromString("1")},
+			{Price: decimal.RequireFromString("150.10"), LongCountPercent: decimal.RequireFromString("1"), ShortCountPercent: decimal.RequireFromString("3")},
+		},
+	}
+	sentiment := book.Sentiment()
+	if percent, ok := sentiment.LongPercent(); !ok || !percent.Equal(decimal.NewFromInt(50)) {
+		t.Error("Got ", percent, ok)
+	}
+	distribution := book.Distribution()
+	if !distribution.Below.Long.Equal(decimal.NewFromInt(3)) || !distribution.Above.Short.Equal(decimal.NewFromInt(3)) {
+		t.Error("Got ", distribution)
+	}
+}
@@ -0,0 +1,320 @@
+package oanda_sdk
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrBookNotFound is returned by GetInstrumentOrderBook and GetInstrumentPositionBook when there is no snapshot at
+// the requested time, e.g. while the market is closed
+var ErrBookNotFound = errors.New("no book snapshot at the time")
+
+// BookSnapshotInterval is the cadence of the order book and position book snapshots, which are taken at the full
+// hour and every 20 minutes after it
+const BookSnapshotInterval = 20 * time.Minute
+
+// BookStore persists order book and position book snapshots
+type BookStore interface {
+	// SaveOrderBook stores the order book snapshot, replacing a stored snapshot of the same instrument and time.
+	SaveOrderBook(book OrderBook) error
+
+	// SavePositionBook stores the position book snapshot, replacing a stored snapshot of the same instrument and time.
+	SavePositionBook(book PositionBook) error
+
+	// OrderBooks returns the stored order book snapshots of the instrument taken within the time range, ordered by
+	// time.
+	OrderBooks(instrument string, within TimeRange) ([]OrderBook, error)
+
+	// PositionBooks returns the stored position book snapshots of the instrument taken within the time range,
+	// ordered by time.
+	PositionBooks(instrument string, within TimeRange) ([]PositionBook, error)
+}
+
+// bookSeries holds the snapshots of a book by time
+type bookSeries[T any] map[time.Time]T
+
+// within returns the snapshots taken within the time range, ordered by time
+func (bs bookSeries[T]) within(r TimeRange) []T {
+	var books []T
+	for _, t := range bs.times() {
+		if !t.Before(r.From) && t.Before(r.To) {
+			books = append(books, bs[t])
+		}
+	}
+	return books
+}
+
+// times returns the times of the snapshots in order
+func (bs bookSeries[T]) times() []time.Time {
+	times := make([]time.Time, 0, len(bs))
+	for t := range bs {
+		times = append(times, t)
+	}
+	sort.Slice(times, func(i, j int) bool {
+		return times[i].Before(times[j])
+	})
+	return times
+}
+
+// MemoryBookStore is a BookStore keeping the snapshots in memory
+type MemoryBookStore struct {
+	mu            sync.RWMutex
+	orderBooks    map[string]bookSeries[OrderBook]
+	positionBooks map[string]bookSeries[PositionBook]
+}
+
+// NewMemoryBookStore creates an empty MemoryBookStore
+func NewMemoryBookStore() *MemoryBookStore {
+	return &MemoryBookStore{
+		orderBooks:    make(map[string]bookSeries[OrderBook]),
+		positionBooks: make(map[string]bookSeries[PositionBook]),
+	}
+}
+
+// SaveOrderBook stores the order book snapshot
+func (s *MemoryBookStore) SaveOrderBook(book OrderBook) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.orderBooks[book.Instrument] == nil {
+		s.orderBooks[book.Instrument] = make(bookSeries[OrderBook])
+	}
+	s.orderBooks[book.Instrument][book.Time.UTC()] = book
+	return nil
+}
+
+// SavePositionBook stores the position book snapshot
+func (s *MemoryBookStore) SavePositionBook(book PositionBook) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.positionBooks[book.Instrument] == nil {
+		s.positionBooks[book.Instrument] = make(bookSeries[PositionBook])
+	}
+	s.positionBooks[book.Instrument][book.Time.UTC()] = book
+	return nil
+}
+
+// OrderBooks returns the stored order book snapshots of the instrument taken within the time range
+func (s *MemoryBookStore) OrderBooks(instrument string, within TimeRange) ([]OrderBook, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.orderBooks[instrument].within(within), nil
+}
+
+// PositionBooks returns the stored position book snapshots of the instrument taken within the time range
+func (s *MemoryBookStore) PositionBooks(instrument string, within TimeRange) ([]PositionBook, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.positionBooks[instrument].within(within), nil
+}
+
+// FileBookStore is a BookStore keeping the snapshots of each instrument, book and (UTC) day in a JSON Lines file in a
+// directory, one snapshot per line. Saving rewrites the file of the day, the files are replaced atomically.
+type FileBookStore struct {
+	dir string
+	mu  sync.RWMutex
+}
+
+// NewFileBookStore creates a FileBookStore in the directory, which is created if it does not exist
+func NewFileBookStore(dir string) (*FileBookStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &FileBookStore{dir: dir}, nil
+}
+
+// SaveOrderBook stores the order book snapshot
+func (s *FileBookStore) SaveOrderBook(book OrderBook) error {
+	return saveBookFile(s, book.Instrument, "orderBook", book, func(b OrderBook) time.Time { return b.Time })
+}
+
+// SavePositionBook stores the position book snapshot
+func (s *FileBookStore) SavePositionBook(book PositionBook) error {
+	return saveBookFile(s, book.Instrument, "positionBook", book, func(b PositionBook) time.Time { return b.Time })
+}
+
+// OrderBooks returns the stored order book snapshots of the instrument taken within the time range
+func (s *FileBookStore) OrderBooks(instrument string, within TimeRange) ([]OrderBook, error) {
+	return loadBookFiles(s, instrument, "orderBook", within, func(b OrderBook) time.Time { return b.Time })
+}
+
+// PositionBooks returns the stored position book snapshots of the instrument taken within the time range
+func (s *FileBookStore) PositionBooks(instrument string, within TimeRange) ([]PositionBook, error) {
+	return loadBookFiles(s, instrument, "positionBook", within, func(b PositionBook) time.Time { return b.Time })
+}
+
+func (s *FileBookStore) path(instrument, book string, day time.Time) string {
+	return filepath.Join(s.dir, fmt.Sprintf("%s-%s-%s.jsonl", instrument, book, day.UTC().Format(time.DateOnly)))
+}
+
+// saveBookFile adds the snapshot to the file of its day
+func saveBookFile[T any](s *FileBookStore, instrument, book string, snapshot T, timeOf func(T) time.Time) error {
+	t := timeOf(snapshot)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	path := s.path(instrument, book, t)
+	series, err := readBookFile(path, timeOf)
+	if err != nil {
+		return err
+	}
+	series[t.UTC()] = snapshot
+	return writeFileAtomically(path, func(f *os.File) error {
+		encoder := json.NewEncoder(f)
+		for _, t := range series.times() {
+			if err := encoder.Encode(series[t]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// loadBookFiles reads the snapshots taken within the time range from the files of its days
+func loadBookFiles[T any](s *FileBookStore, instrument, book string, within TimeRange, timeOf func(T) time.Time) ([]T, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var books []T
+	for day := within.From.UTC().Truncate(24 * time.Hour); day.Before(within.To); day = day.AddDate(0, 0, 1) {
+		series, err := readBookFile(s.path(instrument, book, day), timeOf)
+		if err != nil {
+			return nil, err
+		}
+		books = append(books, series.within(within)...)
+	}
+	return books, nil
+}
+
+// readBookFile reads the snapshots of a file, which may not exist
+func readBookFile[T any](path string, timeOf func(T) time.Time) (bookSeries[T], error) {
+	series := make(bookSeries[T])
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return series, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	decoder := json.NewDecoder(f)
+	for decoder.More() {
+		var snapshot T
+		if err := decoder.Decode(&snapshot); err != nil {
+			return nil, err
+		}
+		series[timeOf(snapshot).UTC()] = snapshot
+	}
+	return series, nil
+}
+
+// BookCollector backfills the order book and position book snapshots of instruments into a BookStore
+type BookCollector struct {
+	client *Client
+	store  BookStore
+}
+
+// BookBackfill reports the outcome of backfilling the snapshots of a book over a time range
+type BookBackfill struct {
+	// The number of snapshots fetched and saved.
+	Saved int
+
+	// The number of snapshots which were already stored.
+	Existing int
+
+	// The snapshot times without a snapshot, e.g. while the market was closed.
+	Missing []time.Time
+}
+
+// NewBookCollector creates a BookCollector fetching the snapshots with the client
+func NewBookCollector(client *Client, store BookStore) *BookCollector {
+	return &BookCollector{client: client, store: store}
+}
+
+// BackfillOrderBooks fetches and saves the order book snapshots of the instrument taken between from and to, which
+// are not stored yet (see BookSnapshotTimes). The snapshots are fetched concurrently, the returned error joins the
+// errors of the snapshots which could not be fetched or saved.
+func (bc *BookCollector) BackfillOrderBooks(instrument string, from, to time.Time) (*BookBackfill, error) {
+	stored, err := bc.store.OrderBooks(instrument, TimeRange{From: from, To: to})
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[time.Time]bool, len(stored))
+	for _, book := range stored {
+		existing[book.Time.UTC()] = true
+	}
+	return backfillBooks(BookSnapshotTimes(from, to), existing, func(t time.Time) error {
+		response, err := bc.client.GetInstrumentOrderBook(instrument, &t)
+		if err != nil {
+			return err
+		}
+		return bc.store.SaveOrderBook(response.OrderBook)
+	})
+}
+
+// BackfillPositionBooks fetches and saves the position book snapshots of the instrument taken between from and to,
+// which are not stored yet (see BackfillOrderBooks)
+func (bc *BookCollector) BackfillPositionBooks(instrument string, from, to time.Time) (*BookBackfill, error) {
+	stored, err := bc.store.PositionBooks(instrument, TimeRange{From: from, To: to})
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[time.Time]bool, len(stored))
+	for _, book := range stored {
+		existing[book.Time.UTC()] = true
+	}
+	return backfillBooks(BookSnapshotTimes(from, to), existing, func(t time.Time) error {
+		response, err := bc.client.GetInstrumentPositionBook(instrument, &t)
+		if err != nil {
+			return err
+		}
+		return bc.store.SavePositionBook(response.PositionBook)
+	})
+}
+
+// backfillBooks fetches the snapshots at the times which do not exist yet
+func backfillBooks(times []time.Time, existing map[time.Time]bool, fetch func(time.Time) error) (*BookBackfill, error) {
+	backfill := &BookBackfill{}
+	var missing []time.Time
+	for _, t := range times {
+		if existing[t] {
+			backfill.Existing++
+		} else {
+			missing = append(missing, t)
+		}
+	}
+	errs := make([]error, len(missing))
+	forEachConcurrently(len(missing), func(i int) {
+		errs[i] = fetch(missing[i])
+	})
+	var failed []error
+	for i, err := range errs {
+		switch {
+		case err == nil:
+			backfill.Saved++
+		case errors.Is(err, ErrBookNotFound):
+			backfill.Missing = append(backfill.Missing, missing[i])
+		default:
+			failed = append(failed, fmt.Errorf("snapshot at %s: %w", missing[i].Format(time.RFC3339), err))
+		}
+	}
+	return backfill, errors.Join(failed...)
+}
+
+// BookSnapshotTimes returns the times of the order book and position book snapshots between from (inclusive) and to
+// (exclusive)
+func BookSnapshotTimes(from, to time.Time) []time.Time {
+	var times []time.Time
+	t := from.UTC().Truncate(BookSnapshotInterval)
+	if t.Before(from) {
+		t = t.Add(BookSnapshotInterval)
+	}
+	for ; t.Before(to); t = t.Add(BookSnapshotInterval) {
+		times = append(times, t)
+	}
+	return times
+}
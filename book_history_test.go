@@ -0,0 +1,110 @@
+package oanda_sdk
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+var bookStart = time.Date(2024, 6, 3, 0, 0, 0, 0, time.UTC)
+
+func TestBookSnapshotTimes(t *testing.T) {
+	times := BookSnapshotTimes(bookStart.Add(5*time.Minute), bookStart.Add(time.Hour))
+	if len(times) != 2 || !times[0].Equal(bookStart.Add(20*time.Minute)) || !times[1].Equal(bookStart.Add(40*time.Minute)) {
+		t.Error("Got ", times)
+	}
+	if times := BookSnapshotTimes(bookStart, bookStart.Add(20*time.Minute)); len(times) != 1 || !times[0].Equal(bookStart) {
+		t.Error("Got ", times)
+	}
+}
+
+func TestBookCollectorBackfill(t *testing.T) {
+	var mu sync.Mutex
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		at, err := time.Parse(time.RFC3339, r.URL.Query().Get("time"))
+		if err != nil {
+			t.Error("Got ", r.URL.RawQuery)
+		}
+		mu.Lock()
+		requested = append(requested, r.URL.Path+" "+at.Format(time.TimeOnly))
+		mu.Unlock()
+		switch {
+		case at.Equal(bookStart.Add(40 * time.Minute)):
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"errorMessage": "No snapshot found"}`)
+		case r.URL.Path == "/v3/instruments/EUR_USD/positionBook":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			fmt.Fprintf(w, `{"orderBook": {"instrument": "EUR_USD", "time": "%s", "price": "1.1", "bucketWidth": "0.0005", "buckets": []}}`, at.Format(time.RFC3339))
+		}
+	}))
+	defer server.Close()
+	store := NewMemoryBookStore()
+	if err := store.SaveOrderBook(OrderBook{Instrument: "EUR_USD", Time: bookStart}); err != nil {
+		t.Fatal("Got ", err)
+	}
+	collector := NewBookCollector(NewClient(server.URL, "token", server.Client()), store)
+
+	backfill, err := collector.BackfillOrderBooks("EUR_USD", bookStart, bookStart.Add(time.Hour))
+	if err != nil {
+		t.Fatal("Got ", err)
+	}
+	if backfill.Saved != 1 || backfill.Existing != 1 || len(backfill.Missing) != 1 || !backfill.Missing[0].Equal(bookStart.Add(40*time.Minute)) {
+		t.Error("Got ", backfill)
+	}
+	if len(requested) != 2 {
+		t.Error("Got ", requested)
+	}
+	books, _ := store.OrderBooks("EUR_USD", TimeRange{From: bookStart, To: bookStart.Add(time.Hour)})
+	if len(books) != 2 || !books[1].Time.Equal(bookStart.Add(20*time.Minute)) || !books[1].Price.Equal(decimal.RequireFromString("1.1")) {
+		t.Error("Got ", books)
+	}
+
+	backfill, err = collector.BackfillPositionBooks("EUR_USD", bookStart, bookStart.Add(time.Hour))
+	if err == nil || errors.Is(err, ErrBookNotFound) {
+		t.Error("Got ", err)
+	}
+	if backfill.Saved != 0 || len(backfill.Missing) != 1 {
+		t.Error("Got ", backfill)
+	}
+}
+
+func TestFileBookStoreRoundTrip(t *testing.T) {
+	store, err := NewFileBookStore(t.TempDir())
+	if err != nil {
+		t.Fatal("Got ", err)
+	}
+	late := bookStart.Add(23*time.Hour + 40*time.Minute)
+	for _, at := range []time.Time{late, bookStart, late.Add(BookSnapshotInterval)} {
+		book := PositionBook{Instrument: "USD_JPY", Time: at, Price: decimal.RequireFromString("150.123")}
+		if err := store.SavePositionBook(book); err != nil {
+			t.Fatal("Got ", err)
+		}
+	}
+	// Saving a snapshot again replaces it
+	if err := store.SavePositionBook(PositionBook{Instrument: "USD_JPY", Time: bookStart, Price: decimal.RequireFromString("150.5")}); err != nil {
+		t.Fatal("Got ", err)
+	}
+
+	books, err := store.PositionBooks("USD_JPY", TimeRange{From: bookStart, To: bookStart.Add(48 * time.Hour)})
+	if err != nil {
+		t.Fatal("Got ", err)
+	}
+	if len(books) != 3 || !books[0].Price.Equal(decimal.RequireFromString("150.5")) || !books[2].Time.Equal(late.Add(BookSnapshotInterval)) {
+		t.Error("Got ", books)
+	}
+	books, err = store.PositionBooks("USD_JPY", TimeRange{From: late, To: late.Add(time.Minute)})
+	if err != nil || len(books) != 1 || !books[0].Time.Equal(late) {
+		t.Error("Got ", books, err)
+	}
+	if books, err := store.OrderBooks("USD_JPY", TimeRange{From: bookStart, To: late}); err != nil || len(books) != 0 {
+		t.Error("Got ", books, err)
+	}
+}
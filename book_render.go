@@ -0,0 +1,194 @@
+package oanda_sdk
+
+import (
+	"encoding/csv"
+	"fmt"
+	"github.com/shopspring/decimal"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// BookHistogramOptions configure the rendering of order books and position books as text histograms
+type BookHistogramOptions struct {
+	// The multiple of the book's BucketWidth covered by each row (see OrderBook.Regroup). Default: 1
+	Group int
+
+	// The number of rows rendered above and below the row covering the book's price. Default: 10
+	Rows int
+
+	// The length of the longest bar in characters. Default: 40
+	BarWidth int
+}
+
+func (o BookHistogramOptions) withDefaults() BookHistogramOptions {
+	if o.Group <= 0 {
+		o.Group = 1
+	}
+	if o.Rows <= 0 {
+		o.Rows = 10
+	}
+	if o.BarWidth <= 0 {
+		o.BarWidth = 40
+	}
+	return o
+}
+
+// Regroup returns the order book with its buckets merged into buckets of a multiple of its BucketWidth, aligned to
+// that width. The percentages of the merged buckets are summed.
+func (ob OrderBook) Regroup(multiple int) OrderBook {
+	width, buckets := regroupBuckets(ob.BucketWidth, ob.buckets(), multiple)
+	ob.BucketWidth, ob.Buckets = width, make([]OrderBookBucket, len(buckets))
+	for i, bucket := range buckets {
+		ob.Buckets[i] = OrderBookBucket{Price: bucket.price, LongCountPercent: bucket.side.Long, ShortCountPercent: bucket.side.Short}
+	}
+	return ob
+}
+
+// Regroup returns the position book with its buckets merged into buckets of a multiple of its BucketWidth, aligned to
+// that width. The percentages of the merged buckets are summed.
+func (pb PositionBook) Regroup(multiple int) PositionBook {
+	width, buckets := regroupBuckets(pb.BucketWidth, pb.buckets(), multiple)
+	pb.BucketWidth, pb.Buckets = width, make([]PositionBookBucket, len(buckets))
+	for i, bucket := range buckets {
+		pb.Buckets[i] = PositionBookBucket{Price: bucket.price, LongCountPercent: bucket.side.Long, ShortCountPercent: bucket.side.Short}
+	}
+	return pb
+}
+
+// Histogram renders the order book as a text histogram centred on its price, with a row per bucket from the highest
+// to the lowest price. The short orders are drawn to the left of the prices and the long orders to the right, and the
+// row covering the price is marked with '>'.
+func (ob OrderBook) Histogram(options BookHistogramOptions) string {
+	title := fmt.Sprintf("%s order book at %s, price %s", ob.Instrument, ob.Time.UTC().Format(time.RFC3339), ob.Price)
+	return renderHistogram(title, ob.Price, ob.BucketWidth, ob.buckets(), options)
+}
+
+// Histogram renders the position book as a text histogram centred on its price (see OrderBook.Histogram)
+func (pb PositionBook) Histogram(options BookHistogramOptions) string {
+	title := fmt.Sprintf("%s position book at %s, price %s", pb.Instrument, pb.Time.UTC().Format(time.RFC3339), pb.Price)
+	return renderHistogram(title, pb.Price, pb.BucketWidth, pb.buckets(), options)
+}
+
+// WriteOrderBooksCSV writes the buckets of the order books as CSV with a header row, one row per bucket with the
+// instrument and time of its book
+func WriteOrderBooksCSV(w io.Writer, books ...OrderBook) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(bookCSVHeader); err != nil {
+		return err
+	}
+	for _, book := range books {
+		if err := writeBookCSV(writer, book.Instrument, book.Time, book.buckets()); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// WritePositionBooksCSV writes the buckets of the position books as CSV with a header row, one row per bucket with the
+// instrument and time of its book
+func WritePositionBooksCSV(w io.Writer, books ...PositionBook) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(bookCSVHeader); err != nil {
+		return err
+	}
+	for _, book := range books {
+		if err := writeBookCSV(writer, book.Instrument, book.Time, book.buckets()); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+var bookCSVHeader = []string{"instrument", "time", "price", "long_percent", "short_percent"}
+
+func writeBookCSV(writer *csv.Writer, instrument string, t time.Time, buckets []bookBucket) error {
+	for _, bucket := range buckets {
+		err := writer.Write([]string{
+			instrument,
+			t.UTC().Format(time.RFC3339),
+			bucket.price.String(),
+			bucket.side.Long.String(),
+			bucket.side.Short.String(),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// regroupBuckets merges the buckets into buckets of a multiple of the width, ordered by price
+func regroupBuckets(width decimal.Decimal, buckets []bookBucket, multiple int) (decimal.Decimal, []bookBucket) {
+	if multiple <= 1 || !width.IsPositive() {
+		return width, buckets
+	}
+	width = width.Mul(decimal.NewFromInt(int64(multiple)))
+	merged := make(map[string]*bookBucket)
+	for _, bucket := range buckets {
+		price := bucket.price.Div(width).Floor().Mul(width)
+		group, ok := merged[price.String()]
+		if !ok {
+			group = &bookBucket{price: price}
+			merged[price.String()] = group
+		}
+		group.side.Long = group.side.Long.Add(bucket.side.Long)
+		group.side.Short = group.side.Short.Add(bucket.side.Short)
+	}
+	regrouped := make([]bookBucket, 0, len(merged))
+	for _, bucket := range merged {
+		regrouped = append(regrouped, *bucket)
+	}
+	sort.Slice(regrouped, func(i, j int) bool {
+		return regrouped[i].price.LessThan(regrouped[j].price)
+	})
+	return width, regrouped
+}
+
+// renderHistogram renders the buckets around the price
+func renderHistogram(title string, price, width decimal.Decimal, buckets []bookBucket, options BookHistogramOptions) string {
+	options = options.withDefaults()
+	width, buckets = regroupBuckets(width, buckets, options.Group)
+	var out strings.Builder
+	out.WriteString(title + "\n")
+	if !width.IsPositive() {
+		return out.String()
+	}
+	byPrice := make(map[string]BookSide, len(buckets))
+	for _, bucket := range buckets {
+		byPrice[bucket.price.String()] = bucket.side
+	}
+	// The rows, from the highest price down, with the row covering the price in the middle
+	centre := price.Div(width).Floor().Mul(width)
+	rows := make([]bookBucket, 0, 2*options.Rows+1)
+	maximum := decimal.Zero
+	for i := options.Rows; i >= -options.Rows; i-- {
+		row := bookBucket{price: centre.Add(width.Mul(decimal.NewFromInt(int64(i))))}
+		row.side = byPrice[row.price.String()]
+		maximum = decimal.Max(maximum, row.side.Long, row.side.Short)
+		rows = append(rows, row)
+	}
+	places := max(-width.Exponent(), -price.Exponent(), 0)
+	bar := func(percent decimal.Decimal) string {
+		if !maximum.IsPositive() || !percent.IsPositive() {
+			return ""
+		}
+		length := percent.Mul(decimal.NewFromInt(int64(options.BarWidth))).Div(maximum).Round(0).IntPart()
+		return strings.Repeat("#", max(int(length), 1))
+	}
+	fmt.Fprintf(&out, "%7s %*s  %-*s %*s %s\n", "short%", options.BarWidth, "", len(centre.StringFixed(places)), "price", options.BarWidth, "", "long%")
+	for _, row := range rows {
+		marker := " "
+		if row.price.Equal(centre) {
+			marker = ">"
+		}
+		fmt.Fprintf(&out, "%7s %*s %s%s %-*s %s\n",
+			row.side.Short.StringFixed(2), options.BarWidth, bar(row.side.Short),
+			marker, row.price.StringFixed(places),
+			options.BarWidth, bar(row.side.Long), row.side.Long.StringFixed(2))
+	}
+	return out.String()
+}
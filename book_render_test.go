@@ -0,0 +1,79 @@
+package oanda_sdk
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func renderOrderBook() OrderBook {
+	return OrderBook{
+		Instrument:  "EUR_USD",
+		Time:        bookStart.Add(20 * time.Minute),
+		Price:       decimal.RequireFromString("1.1012"),
+		BucketWidth: decimal.RequireFromString("0.0005"),
+		Buckets: []OrderBookBucket{
+			orderBookBucket("1.1005", "0.4", "0.1"),
+			orderBookBucket("1.1010", "0.2", "0.3"),
+			orderBookBucket("1.1015", "0.8", "0.4"),
+		},
+	}
+}
+
+func TestOrderBookHistogram(t *testing.T) {
+	histogram := renderOrderBook().Histogram(BookHistogramOptions{Rows: 1, BarWidth: 4})
+	expected := strings.Join([]string{
+		"EUR_USD order book at 2024-06-03T00:20:00Z, price 1.1012",
+		" short%       price       long%",
+		"   0.40   ##  1.1015 #### 0.80",
+		"   0.30   ## >1.1010 #    0.20",
+		"   0.10    #  1.1005 ##   0.40",
+		"",
+	}, "\n")
+	if histogram != expected {
+		t.Error("Got\n", histogram)
+	}
+}
+
+func TestOrderBookRegroup(t *testing.T) {
+	book := renderOrderBook().Regroup(2)
+	if !book.BucketWidth.Equal(decimal.RequireFromString("0.001")) || len(book.Buckets) != 2 {
+		t.Fatal("Got ", book)
+	}
+	if !book.Buckets[0].Price.Equal(decimal.RequireFromString("1.1")) || !book.Buckets[0].LongCountPercent.Equal(decimal.RequireFromString("0.4")) {
+		t.Error("Got ", book.Buckets[0])
+	}
+	if !book.Buckets[1].Price.Equal(decimal.RequireFromString("1.101")) || !book.Buckets[1].ShortCountPercent.Equal(decimal.RequireFromString("0.7")) {
+		t.Error("Got ", book.Buckets[1])
+	}
+	histogram := renderOrderBook().Histogram(BookHistogramOptions{Group: 2, Rows: 1, BarWidth: 4})
+	if !strings.Contains(histogram, "   0.70  ### >1.1010 #### 1.00") {
+		t.Error("Got\n", histogram)
+	}
+}
+
+func TestWriteBooksCSV(t *testing.T) {
+	var buffer bytes.Buffer
+	if err := WriteOrderBooksCSV(&buffer, renderOrderBook()); err != nil {
+		t.Fatal("Got ", err)
+	}
+	expected := "instrument,time,price,long_percent,short_percent\n" +
+		"EUR_USD,2024-06-03T00:20:00Z,1.1005,0.4,0.1\n" +
+		"EUR_USD,2024-06-03T00:20:00Z,1.101,0.2,0.3\n" +
+		"EUR_USD,2024-06-03T00:20:00Z,1.1015,0.8,0.4\n"
+	if buffer.String() != expected {
+		t.Error("Got ", buffer.String())
+	}
+
+	buffer.Reset()
+	book := PositionBook{Instrument: "USD_JPY", Time: bookStart, Buckets: []PositionBookBucket{{Price: decimal.RequireFromString("150.05"), LongCountPercent: decimal.RequireFromString("1.25"), ShortCountPercent: decimal.Zero}}}
+	if err := WritePositionBooksCSV(&buffer, book, book); err != nil {
+		t.Fatal("Got ", err)
+	}
+	if lines := strings.Split(strings.TrimSpace(buffer.String()), "\n"); len(lines) != 3 || lines[1] != "USD_JPY,2024-06-03T00:00:00Z,150.05,1.25,0" {
+		t.Error("Got ", lines)
+	}
+}
@@ -0,0 +1,237 @@
+package oanda_sdk
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// bulkOrderConcurrency is the number of requests the bulk Order operations have in flight at once. The requests are
+// still subject to the rate limit of the Client.
+const bulkOrderConcurrency = 8
+
+// OrderFilter selects the Orders affected by a bulk Order operation. Criteria left unset match every Order.
+type OrderFilter struct {
+	// The instrument of the Orders. Orders dependent on a Trade match the instrument of their Trade.
+	Instrument *string
+
+	// The types of the Orders.
+	Types []OrderType
+
+	// The tag from the client extensions of the Orders.
+	Tag *ClientTag
+
+	// The state of the Orders. The bulk operations only ever see PENDING Orders, so any other state matches nothing.
+	State *OrderState
+}
+
+// Matches reports whether the Order with the given instrument is selected by the filter
+func (f OrderFilter) Matches(order Order, instrument string) bool {
+	if f.Instrument != nil && *f.Instrument != instrument {
+		return false
+	}
+	if len(f.Types) > 0 && !slices.Contains(f.Types, order.GetType()) {
+		return false
+	}
+	if f.Tag != nil && order.GetClientExtensions().Tag != *f.Tag {
+		return false
+	}
+	if f.State != nil && order.GetState() != *f.State {
+		return false
+	}
+	return true
+}
+
+// OrderCancelResult is the outcome of cancelling a single Order
+type OrderCancelResult struct {
+	// The ID of the Order.
+	OrderID OrderID
+
+	// The response to the cancellation, only set if the Order was cancelled.
+	Response *CancelAccountOrderResponse
+
+	// The reason the cancellation failed.
+	Err error
+}
+
+// CancelOrdersReport lists the outcome of cancelling each of the Orders selected by a bulk cancellation
+type CancelOrdersReport struct {
+	Results []OrderCancelResult
+}
+
+// Failed returns the results of the Orders which could not be cancelled
+func (r CancelOrdersReport) Failed() []OrderCancelResult {
+	var failed []OrderCancelResult
+	for _, result := range r.Results {
+		if result.Err != nil {
+			failed = append(failed, result)
+		}
+	}
+	return failed
+}
+
+// Err joins the errors of all failed cancellations, it is nil when every Order was cancelled
+func (r CancelOrdersReport) Err() error {
+	var errs []error
+	for _, result := range r.Failed() {
+		errs = append(errs, fmt.Errorf("order %s: %w", result.OrderID, result.Err))
+	}
+	return errors.Join(errs...)
+}
+
+// OrderReplaceResult is the outcome of replacing a single Order
+type OrderReplaceResult struct {
+	// The ID of the replaced Order.
+	OrderID OrderID
+
+	// The response to the replacement, only set if the Order was replaced.
+	Response *ReplaceAccountOrderResponse
+
+	// The reason the replacement failed.
+	Err error
+}
+
+// ReplaceOrdersReport lists the outcome of replacing each of the Orders selected by a bulk replacement
+type ReplaceOrdersReport struct {
+	Results []OrderReplaceResult
+}
+
+// Failed returns the results of the Orders which could not be replaced
+func (r ReplaceOrdersReport) Failed() []OrderReplaceResult {
+	var failed []OrderReplaceResult
+	for _, result := range r.Results {
+		if result.Err != nil {
+			failed = append(failed, result)
+		}
+	}
+	return failed
+}
+
+// Err joins the errors of all failed replacements, it is nil when every Order was replaced
+func (r ReplaceOrdersReport) Err() error {
+	var errs []error
+	for _, result := range r.Failed() {
+		errs = append(errs, fmt.Errorf("order %s: %w", result.OrderID, result.Err))
+	}
+	return errors.Join(errs...)
+}
+
+// CancelAllOrders cancels all pending Orders in an Account selected by the filter. The cancellations are sent
+// concurrently; a failure to cancel one Order does not prevent the others from being cancelled. The returned error is
+// only set when the pending Orders could not be listed, the outcome for each Order is in the report.
+func (c *Client) CancelAllOrders(accountID AccountID, filter OrderFilter) (*CancelOrdersReport, error) {
+	orders, err := c.filterPendingOrders(accountID, filter)
+	if err != nil {
+		return nil, err
+	}
+	report := &CancelOrdersReport{Results: make([]OrderCancelResult, len(orders))}
+	forEachConcurrently(len(orders), func(i int) {
+		orderID := OrderID(orders[i].GetId())
+		response, err := c.CancelAccountOrder(accountID, OrderByID(orderID))
+		report.Results[i] = OrderCancelResult{OrderID: orderID, Response: response, Err: err}
+	})
+	return report, nil
+}
+
+// ReplaceOrders replaces the pending Orders in an Account selected by the filter with the Orders built by the replace
+// function. Orders for which the function returns nil are left untouched. The replacements are sent concurrently; a
+// failure to replace one Order does not prevent the others from being replaced. The returned error is only set when
+// the pending Orders could not be listed, the outcome for each Order is in the report.
+func (c *Client) ReplaceOrders(accountID AccountID, filter OrderFilter, replace func(Order) OrderRequest) (*ReplaceOrdersReport, error) {
+	orders, err := c.filterPendingOrders(accountID, filter)
+	if err != nil {
+		return nil, err
+	}
+	var replaced []Order
+	var requests []OrderRequest
+	for _, order := range orders {
+		if request := replace(order); request != nil {
+			replaced = append(replaced, order)
+			requests = append(requests, request)
+		}
+	}
+	report := &ReplaceOrdersReport{Results: make([]OrderReplaceResult, len(replaced))}
+	forEachConcurrently(len(replaced), func(i int) {
+		orderID := OrderID(replaced[i].GetId())
+		response, err := c.ReplaceAccountOrder(accountID, OrderByID(orderID), requests[i])
+		report.Results[i] = OrderReplaceResult{OrderID: orderID, Response: response, Err: err}
+	})
+	return report, nil
+}
+
+// filterPendingOrders lists the pending Orders of the Account selected by the filter. The open Trades are only
+// fetched when the instrument of an Order dependent on a Trade is needed.
+func (c *Client) filterPendingOrders(accountID AccountID, filter OrderFilter) ([]Order, error) {
+	response, err := c.GetAccountPendingOrders(accountID)
+	if err != nil {
+		return nil, err
+	}
+	var tradeInstruments map[TradeID]string
+	var orders []Order
+	for _, order := range response.Orders {
+		instrument, tradeID := orderInstrument(order)
+		if filter.Instrument != nil && tradeID != "" {
+			if tradeInstruments == nil {
+				trades, err := c.GetAccountOpenTrades(accountID)
+				if err != nil {
+					return nil, err
+				}
+				tradeInstruments = make(map[TradeID]string, len(trades.Trades))
+				for _, trade := range trades.Trades {
+					tradeInstruments[trade.Id] = trade.Instrument
+				}
+			}
+			instrument = tradeInstruments[tradeID]
+		}
+		if filter.Matches(order, instrument) {
+			orders = append(orders, order)
+		}
+	}
+	return orders, nil
+}
+
+// orderInstrument returns the instrument of the Order, or the ID of the Trade for Orders dependent on a Trade
+func orderInstrument(order Order) (string, TradeID) {
+	switch o := order.(type) {
+	case MarketOrder:
+		return o.Instrument, ""
+	case FixedPriceOrder:
+		return o.Instrument, ""
+	case LimitOrder:
+		return o.Instrument, ""
+	case StopOrder:
+		return o.Instrument, ""
+	case MarketIfTouchedOrder:
+		return o.Instrument, ""
+	case TakeProfitOrder:
+		return "", o.TradeID
+	case StopLossOrder:
+		return "", o.TradeID
+	case GuaranteedStopLossOrder:
+		return "", o.TradeID
+	case TrailingStopLossOrder:
+		return "", o.TradeID
+	}
+	return "", ""
+}
+
+// forEachConcurrently calls the function for each index in [0, n), with at most bulkOrderConcurrency calls in flight
+func forEachConcurrently(n int, f func(i int)) {
+	var wg sync.WaitGroup
+	indexes := make(chan int)
+	for w := 0; w < min(n, bulkOrderConcurrency); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				f(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+}
@@ -0,0 +1,158 @@
+package oanda_sdk
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func newBulkOrdersServer(t *testing.T, cancelled *[]string) *httptest.Server {
+	var mu sync.Mutex
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/v3/accounts/001/pendingOrders":
+			body, err := os.ReadFile("test/pendingOrdersResponse.json")
+			if err != nil {
+				t.Error(err)
+			}
+			w.Write(body)
+		case r.URL.Path == "/v3/accounts/001/openTrades":
+			w.Write([]byte(`{"trades": [{"id": "90", "instrument": "EUR_USD"}], "lastTransactionID": "103"}`))
+		case strings.HasSuffix(r.URL.Path, "/cancel"):
+			orderID := strings.Split(r.URL.Path, "/")[5]
+			if orderID == "103" {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"errorMessage": "The Order specified does not exist"}`))
+				return
+			}
+			mu.Lock()
+			*cancelled = append(*cancelled, orderID)
+			mu.Unlock()
+			w.Write([]byte(`{"orderCancelTransaction": {"id": "200", "type": "ORDER_CANCEL", "orderID": "` + orderID + `", "reason": "CLIENT_REQUEST"}, "lastTransactionID": "200"}`))
+		default:
+			t.Error("Got ", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+}
+
+func TestGetAccountOrdersResponseUnmarshalling(t *testing.T) {
+	body, err := os.ReadFile("test/pendingOrdersResponse.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var response GetAccountOrdersResponse
+	err = response.UnmarshalJSON(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(response.Orders) != 3 {
+		t.Fatal("Got ", response.Orders)
+	}
+	if order, ok := response.Orders[0].(LimitOrder); !ok || order.Instrument != "EUR_USD" {
+		t.Errorf("Got %T %v", response.Orders[0], response.Orders[0])
+	}
+	if order, ok := response.Orders[2].(StopLossOrder); !ok || order.TradeID != "90" {
+		t.Errorf("Got %T %v", response.Orders[2], response.Orders[2])
+	}
+}
+
+func TestCancelAllOrdersByInstrument(t *testing.T) {
+	var cancelled []string
+	server := newBulkOrdersServer(t, &cancelled)
+	defer server.Close()
+	client := NewClient(server.URL, "token", server.Client())
+	instrument := "EUR_USD"
+	report, err := client.CancelAllOrders("001", OrderFilter{Instrument: &instrument})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Results) != 2 {
+		t.Fatal("Got ", report.Results)
+	}
+	if len(cancelled) != 1 || cancelled[0] != "101" {
+		t.Error("Got ", cancelled)
+	}
+	failed := report.Failed()
+	if len(failed) != 1 || failed[0].OrderID != "103" || report.Err() == nil {
+		t.Error("Got ", failed)
+	}
+}
+
+func TestCancelAllOrdersByTag(t *testing.T) {
+	var cancelled []string
+	server := newBulkOrdersServer(t, &cancelled)
+	defer server.Close()
+	client := NewClient(server.URL, "token", server.Client())
+	tag := ClientTag("grid")
+	report, err := client.CancelAllOrders("001", OrderFilter{Tag: &tag, Types: []OrderType{Limit}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(cancelled)
+	if report.Err() != nil || len(cancelled) != 2 || cancelled[0] != "101" || cancelled[1] != "102" {
+		t.Error("Got ", report, cancelled)
+	}
+}
+
+func TestReplaceOrdersReportsPartialFailure(t *testing.T) {
+	var mu sync.Mutex
+	var replaced []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/v3/accounts/001/pendingOrders":
+			body, err := os.ReadFile("test/pendingOrdersResponse.json")
+			if err != nil {
+				t.Error(err)
+			}
+			w.Write(body)
+		case r.Method == http.MethodPut && strings.HasPrefix(r.URL.Path, "/v3/accounts/001/orders/"):
+			orderID := strings.Split(r.URL.Path, "/")[5]
+			if orderID == "102" {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"errorCode": "PRICE_PRECISION_EXCEEDED", "errorMessage": "The price has too many decimal places"}`))
+				return
+			}
+			mu.Lock()
+			replaced = append(replaced, orderID)
+			mu.Unlock()
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"orderCancelTransaction": {"id": "200", "type": "ORDER_CANCEL", "orderID": "` + orderID + `", "reason": "CLIENT_REQUEST_REPLACED"}, "orderCreateTransaction": {"id": "201", "type": "LIMIT_ORDER", "instrument": "EUR_USD", "units": "100", "price": "1.07900"}, "lastTransactionID": "201"}`))
+		default:
+			t.Error("Got ", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, "token", server.Client())
+	report, err := client.ReplaceOrders("001", OrderFilter{}, func(order Order) OrderRequest {
+		limit, ok := order.(LimitOrder)
+		if !ok {
+			return nil
+		}
+		return LimitOrderRequest{Instrument: limit.Instrument, Units: limit.Units, Price: limit.Price.Sub(decimal.RequireFromString("0.001"))}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The Stop Loss Order is left untouched
+	if len(report.Results) != 2 {
+		t.Fatal("Got ", report.Results)
+	}
+	if len(replaced) != 1 || replaced[0] != "101" {
+		t.Error("Got ", replaced)
+	}
+	if report.Results[0].OrderID != "101" || report.Results[0].Err != nil || report.Results[0].Response == nil {
+		t.Error("Got ", report.Results[0])
+	}
+	failed := report.Failed()
+	if len(failed) != 1 || failed[0].OrderID != "102" || failed[0].Response != nil || report.Err() == nil {
+		t.Error("Got ", failed)
+	}
+}
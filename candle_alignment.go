@@ -0,0 +1,118 @@
+package oanda_sdk
+
+import (
+	"fmt"
+	"time"
+)
+
+// CandleAlignment holds the options aligning the candlesticks of granularities with daily, weekly or monthly
+// alignment, like the ones of GetInstrumentCandlesRequest
+type CandleAlignment struct {
+	// The hour of the day (in the AlignmentTimezone) the days of the candlesticks start at. Default: 17
+	DailyAlignment *int
+
+	// The timezone of the DailyAlignment. Default: America/New_York
+	AlignmentTimezone *string
+
+	// The day of the week the weekly candlesticks start on. Default: Friday
+	WeeklyAlignment *WeeklyAlignment
+}
+
+var weekdays = map[WeeklyAlignment]time.Weekday{
+	Sunday: time.Sunday, Monday: time.Monday, Tuesday: time.Tuesday, Wednesday: time.Wednesday,
+	Thursday: time.Thursday, Friday: time.Friday, Saturday: time.Saturday,
+}
+
+// CandleAligner computes the time-ranges of the candlesticks of a granularity the way OANDA aligns them. Candlesticks
+// of up to a minute are aligned to the minute, the ones of up to an hour to the hour, both in UTC. The coarser
+// candlesticks are aligned to the day starting at the daily alignment hour in the alignment timezone, to the week
+// starting on the weekly alignment day, or to the first day of the month.
+type CandleAligner struct {
+	granularity CandlestickGranularity
+	duration    time.Duration
+	hour        int
+	location    *time.Location
+	weekday     time.Weekday
+}
+
+// NewCandleAligner creates a CandleAligner for the granularity and alignment
+func NewCandleAligner(granularity CandlestickGranularity, alignment CandleAlignment) (*CandleAligner, error) {
+	ca := &CandleAligner{granularity: granularity, duration: granularity.Duration(), hour: 17, weekday: time.Friday}
+	if ca.duration == 0 {
+		return nil, fmt.Errorf("unknown granularity %s", granularity)
+	}
+	if alignment.DailyAlignment != nil {
+		if *alignment.DailyAlignment < 0 || *alignment.DailyAlignment > 23 {
+			return nil, fmt.Errorf("invalid daily alignment %d", *alignment.DailyAlignment)
+		}
+		ca.hour = *alignment.DailyAlignment
+	}
+	timezone := "America/New_York"
+	if alignment.AlignmentTimezone != nil {
+		timezone = *alignment.AlignmentTimezone
+	}
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, err
+	}
+	ca.location = location
+	if alignment.WeeklyAlignment != nil {
+		weekday, ok := weekdays[*alignment.WeeklyAlignment]
+		if !ok {
+			return nil, fmt.Errorf("invalid weekly alignment %s", *alignment.WeeklyAlignment)
+		}
+		ca.weekday = weekday
+	}
+	return ca, nil
+}
+
+// Granularity returns the granularity of the candlesticks
+func (ca *CandleAligner) Granularity() CandlestickGranularity {
+	return ca.granularity
+}
+
+// Period returns the time-range of the candlestick covering the time
+func (ca *CandleAligner) Period(t time.Time) TimeRange {
+	switch {
+	case ca.granularity == M:
+		local := t.In(ca.location)
+		start := time.Date(local.Year(), local.Month(), 1, ca.hour, 0, 0, 0, ca.location)
+		if start.After(t) {
+			start = time.Date(local.Year(), local.Month()-1, 1, ca.hour, 0, 0, 0, ca.location)
+		}
+		return TimeRange{From: start, To: time.Date(start.Year(), start.Month()+1, 1, ca.hour, 0, 0, 0, ca.location)}
+	case ca.granularity == W:
+		start := ca.dayStart(t)
+		for start.Weekday() != ca.weekday {
+			start = ca.addDays(start, -1)
+		}
+		return TimeRange{From: start, To: ca.addDays(start, 7)}
+	case ca.duration > time.Hour:
+		// The last candlestick of a day shortened or lengthened by a DST change ends with the day
+		day := ca.dayStart(t)
+		start := day.Add(t.Sub(day) / ca.duration * ca.duration)
+		end := start.Add(ca.duration)
+		if next := ca.addDays(day, 1); end.After(next) {
+			end = next
+		}
+		return TimeRange{From: start, To: end}
+	default:
+		start := t.UTC().Truncate(ca.duration)
+		return TimeRange{From: start, To: start.Add(ca.duration)}
+	}
+}
+
+// dayStart returns the start of the day covering the time, in the alignment timezone
+func (ca *CandleAligner) dayStart(t time.Time) time.Time {
+	local := t.In(ca.location)
+	start := time.Date(local.Year(), local.Month(), local.Day(), ca.hour, 0, 0, 0, ca.location)
+	if start.After(t) {
+		start = ca.addDays(start, -1)
+	}
+	return start
+}
+
+// addDays moves the start of a day by the number of days, keeping the daily alignment hour across DST changes
+func (ca *CandleAligner) addDays(day time.Time, days int) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day()+days, ca.hour, 0, 0, 0, ca.location)
+}
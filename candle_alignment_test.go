@@ -0,0 +1,56 @@
+package oanda_sdk
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCandleAlignerPeriods(t *testing.T) {
+	winter := time.Date(2024, 1, 10, 12, 34, 56, 0, time.UTC) // Wednesday, 07:34 in New York
+	summer := time.Date(2024, 7, 10, 12, 34, 56, 0, time.UTC) // Wednesday, 08:34 in New York
+	utc := func(month time.Month, day, hour, minute int) time.Time {
+		return time.Date(2024, month, day, hour, minute, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		granularity CandlestickGranularity
+		at          time.Time
+		from, to    time.Time
+	}{
+		{S5, winter, winter.Add(-time.Second), winter.Add(4 * time.Second)},
+		{M1, winter, utc(1, 10, 12, 34), utc(1, 10, 12, 35)},
+		{M15, winter, utc(1, 10, 12, 30), utc(1, 10, 12, 45)},
+		{H1, winter, utc(1, 10, 12, 0), utc(1, 10, 13, 0)},
+		// The days start at 17:00 in New York, which is 22:00 UTC in winter and 21:00 UTC in summer
+		{H4, winter, utc(1, 10, 10, 0), utc(1, 10, 14, 0)},
+		{H4, summer, utc(7, 10, 9, 0), utc(7, 10, 13, 0)},
+		{D, winter, utc(1, 9, 22, 0), utc(1, 10, 22, 0)},
+		{W, winter, utc(1, 5, 22, 0), utc(1, 12, 22, 0)},
+		{M, winter, utc(1, 1, 22, 0), utc(2, 1, 22, 0)},
+	}
+	for _, test := range tests {
+		aligner, err := NewCandleAligner(test.granularity, CandleAlignment{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		period := aligner.Period(test.at)
+		if !period.From.Equal(test.from) || !period.To.Equal(test.to) {
+			t.Error("Got ", test.granularity, " ", period)
+		}
+	}
+}
+
+func TestCandleAlignerHonoursAlignment(t *testing.T) {
+	hour, timezone, weekday := 0, "Europe/Prague", Monday
+	aligner, err := NewCandleAligner(W, CandleAlignment{DailyAlignment: &hour, AlignmentTimezone: &timezone, WeeklyAlignment: &weekday})
+	if err != nil {
+		t.Fatal(err)
+	}
+	period := aligner.Period(time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC))
+	if !period.From.Equal(time.Date(2024, 1, 7, 23, 0, 0, 0, time.UTC)) || !period.To.Equal(time.Date(2024, 1, 14, 23, 0, 0, 0, time.UTC)) {
+		t.Error("Got ", period)
+	}
+	timezone = "Nowhere/Atlantis"
+	if _, err := NewCandleAligner(D, CandleAlignment{AlignmentTimezone: &timezone}); err == nil {
+		t.Error("Expected an error for an unknown timezone")
+	}
+}
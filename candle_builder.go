@@ -0,0 +1,182 @@
+package oanda_sdk
+
+import (
+	"context"
+	"errors"
+	"github.com/shopspring/decimal"
+	"sync"
+	"time"
+)
+
+// InstrumentCandle is a Candlestick of an instrument
+type InstrumentCandle struct {
+	// The instrument of the candlestick.
+	Instrument string
+
+	// The granularity of the candlestick.
+	Granularity CandlestickGranularity
+
+	// The candlestick.
+	Candle Candlestick
+}
+
+// CandleBuilder builds bid, ask and mid candlesticks of a granularity from the prices of the pricing stream. The
+// candlesticks are aligned like the ones of GetInstrumentCandles, and their volume is the number of prices received.
+//
+// A candlestick is completed once a price of the next period arrives or, when running, once its period has passed.
+type CandleBuilder struct {
+	aligner *CandleAligner
+	candles chan InstrumentCandle
+
+	mu      sync.Mutex
+	forming map[string]*formingCandle
+}
+
+type formingCandle struct {
+	period TimeRange
+	candle Candlestick
+}
+
+// NewCandleBuilder creates a CandleBuilder for the granularity and alignment
+func NewCandleBuilder(granularity CandlestickGranularity, alignment CandleAlignment) (*CandleBuilder, error) {
+	aligner, err := NewCandleAligner(granularity, alignment)
+	if err != nil {
+		return nil, err
+	}
+	return &CandleBuilder{
+		aligner: aligner,
+		candles: make(chan InstrumentCandle, 64),
+		forming: make(map[string]*formingCandle),
+	}, nil
+}
+
+// Candles returns the channel Run publishes the completed candlesticks on. The channel must be received from while
+// the CandleBuilder runs, Run blocks until the candlesticks are received.
+func (cb *CandleBuilder) Candles() <-chan InstrumentCandle {
+	return cb.candles
+}
+
+// Run feeds the prices from the channel (typically the one returned by [Client.GetAccountPricingStream]) into the
+// CandleBuilder and publishes the completed candlesticks until the context is done or the channel is closed
+func (cb *CandleBuilder) Run(ctx context.Context, prices <-chan ClientPrice) error {
+	ticker := time.NewTicker(min(cb.aligner.duration, time.Second))
+	defer ticker.Stop()
+	for {
+		var completed []InstrumentCandle
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case now := <-ticker.C:
+			completed = cb.Close(now)
+		case price, ok := <-prices:
+			if !ok {
+				return errors.New("pricing stream closed")
+			}
+			if candle, ok := cb.HandlePrice(price); ok {
+				completed = append(completed, candle)
+			}
+		}
+		for _, candle := range completed {
+			select {
+			case cb.candles <- candle:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+}
+
+// HandlePrice adds the price to the forming candlestick of its instrument. When the price belongs to a later period,
+// the forming candlestick is completed and returned, and a new one is started with the price. Prices older than the
+// forming candlestick are ignored.
+func (cb *CandleBuilder) HandlePrice(price ClientPrice) (InstrumentCandle, bool) {
+	bid, hasBid := price.BestBid()
+	ask, hasAsk := price.BestAsk()
+	if !hasBid && !hasAsk {
+		return InstrumentCandle{}, false
+	}
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	forming, ok := cb.forming[price.Instrument]
+	if ok && price.Time.Before(forming.period.From) {
+		return InstrumentCandle{}, false
+	}
+	var completed *InstrumentCandle
+	if ok && !price.Time.Before(forming.period.To) {
+		completed = cb.complete(price.Instrument, forming)
+		ok = false
+	}
+	if !ok {
+		period := cb.aligner.Period(price.Time)
+		forming = &formingCandle{period: period, candle: Candlestick{Time: period.From}}
+		cb.forming[price.Instrument] = forming
+	}
+	forming.candle.Volume++
+	if hasBid {
+		forming.candle.Bid = addTick(forming.candle.Bid, bid)
+	}
+	if hasAsk {
+		forming.candle.Ask = addTick(forming.candle.Ask, ask)
+	}
+	if hasBid && hasAsk {
+		forming.candle.Mid = addTick(forming.candle.Mid, bid.Add(ask).Div(decimal.NewFromInt(2)))
+	}
+	if completed == nil {
+		return InstrumentCandle{}, false
+	}
+	return *completed, true
+}
+
+// Close completes and returns the forming candlesticks whose period has passed at the time
+func (cb *CandleBuilder) Close(now time.Time) []InstrumentCandle {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	var completed []InstrumentCandle
+	for instrument, forming := range cb.forming {
+		if !now.Before(forming.period.To) {
+			completed = append(completed, *cb.complete(instrument, forming))
+		}
+	}
+	return completed
+}
+
+// Forming returns the candlestick of the instrument which is still forming. The second return value is false when
+// no price of the instrument has been received in the current period.
+func (cb *CandleBuilder) Forming(instrument string) (Candlestick, bool) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	forming, ok := cb.forming[instrument]
+	if !ok {
+		return Candlestick{}, false
+	}
+	candle := forming.candle
+	candle.Bid, candle.Ask, candle.Mid = copyCandleData(candle.Bid), copyCandleData(candle.Ask), copyCandleData(candle.Mid)
+	return candle, true
+}
+
+// complete removes the forming candlestick of the instrument and returns it completed
+func (cb *CandleBuilder) complete(instrument string, forming *formingCandle) *InstrumentCandle {
+	delete(cb.forming, instrument)
+	candle := forming.candle
+	candle.Complete = true
+	return &InstrumentCandle{Instrument: instrument, Granularity: cb.aligner.granularity, Candle: candle}
+}
+
+// addTick adds a price to the candlestick data, starting it if it is nil
+func addTick(data *CandlestickData, price decimal.Decimal) *CandlestickData {
+	if data == nil {
+		return &CandlestickData{Open: price, High: price, Low: price, Close: price}
+	}
+	data.High = decimal.Max(data.High, price)
+	data.Low = decimal.Min(data.Low, price)
+	data.Close = price
+	return data
+}
+
+func copyCandleData(data *CandlestickData) *CandlestickData {
+	if data == nil {
+		return nil
+	}
+	copied := *data
+	return &copied
+}
@@ -0,0 +1,57 @@
+package oanda_sdk
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestCandleBuilderBuildsCandles(t *testing.T) {
+	builder, err := NewCandleBuilder(M1, CandleAlignment{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2024, 1, 10, 10, 0, 0, 0, time.UTC)
+	tick := func(offset time.Duration, bid, ask string) (InstrumentCandle, bool) {
+		return builder.HandlePrice(ClientPrice{
+			Instrument: "EUR_USD",
+			Time:       start.Add(offset),
+			Bids:       []PriceBucket{{Price: decimal.RequireFromString(bid)}},
+			Asks:       []PriceBucket{{Price: decimal.RequireFromString(ask)}},
+		})
+	}
+	for _, offset := range []time.Duration{5 * time.Second, 30 * time.Second, 59 * time.Second} {
+		bid := decimal.RequireFromString("1.1000").Add(decimal.NewFromFloat(offset.Seconds()).Shift(-5))
+		if _, ok := tick(offset, bid.String(), bid.Add(decimal.RequireFromString("0.0002")).String()); ok {
+			t.Error("Got a completed candle at ", offset)
+		}
+	}
+	forming, ok := builder.Forming("EUR_USD")
+	if !ok || forming.Complete || forming.Volume != 3 || forming.Bid.Close.String() != "1.10059" {
+		t.Error("Got ", forming)
+	}
+
+	completed, ok := tick(70*time.Second, "1.0990", "1.0992")
+	if !ok || completed.Instrument != "EUR_USD" || completed.Granularity != M1 || !completed.Candle.Complete {
+		t.Fatal("Got ", completed)
+	}
+	candle := completed.Candle
+	if !candle.Time.Equal(start) || candle.Volume != 3 {
+		t.Error("Got ", candle)
+	}
+	if candle.Bid.Open.String() != "1.10005" || candle.Bid.High.String() != "1.10059" || candle.Ask.Low.String() != "1.10025" || candle.Mid.Close.String() != "1.10069" {
+		t.Error("Got ", candle.Bid, candle.Ask, candle.Mid)
+	}
+
+	if closed := builder.Close(start.Add(110 * time.Second)); len(closed) != 0 {
+		t.Error("Got ", closed)
+	}
+	closed := builder.Close(start.Add(2 * time.Minute))
+	if len(closed) != 1 || !closed[0].Candle.Time.Equal(start.Add(time.Minute)) || closed[0].Candle.Mid.Open.String() != "1.0991" {
+		t.Error("Got ", closed)
+	}
+	if _, ok := builder.Forming("EUR_USD"); ok {
+		t.Error("Expected no forming candle")
+	}
+}
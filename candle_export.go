@@ -0,0 +1,267 @@
+package oanda_sdk
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"github.com/shopspring/decimal"
+	"io"
+	"strconv"
+	"time"
+)
+
+// CandleColumn is a column of the CSV export of candlesticks. Parquet is not supported, as it would need a Parquet
+// library the module does not depend on; notebooks can convert the CSV export instead (e.g. with pandas.read_csv).
+type CandleColumn string
+
+const (
+	// The start time of the candlestick
+	ColumnTime CandleColumn = "time"
+
+	// The volume of the candlestick
+	ColumnVolume CandleColumn = "volume"
+
+	// The flag indicating if the candlestick is complete
+	ColumnComplete CandleColumn = "complete"
+
+	// The open, high, low and close of the bid data
+	ColumnBidOpen  CandleColumn = "bid_open"
+	ColumnBidHigh  CandleColumn = "bid_high"
+	ColumnBidLow   CandleColumn = "bid_low"
+	ColumnBidClose CandleColumn = "bid_close"
+
+	// The open, high, low and close of the ask data
+	ColumnAskOpen  CandleColumn = "ask_open"
+	ColumnAskHigh  CandleColumn = "ask_high"
+	ColumnAskLow   CandleColumn = "ask_low"
+	ColumnAskClose CandleColumn = "ask_close"
+
+	// The open, high, low and close of the mid data
+	ColumnMidOpen  CandleColumn = "mid_open"
+	ColumnMidHigh  CandleColumn = "mid_high"
+	ColumnMidLow   CandleColumn = "mid_low"
+	ColumnMidClose CandleColumn = "mid_close"
+)
+
+// candleComponents are the Price components of the candlestick data columns, in the order of the default columns
+var candleComponents = []struct {
+	name    string
+	columns [4]CandleColumn
+	data    func(*Candlestick) **CandlestickData
+}{
+	{"bid", [4]CandleColumn{ColumnBidOpen, ColumnBidHigh, ColumnBidLow, ColumnBidClose}, func(c *Candlestick) **CandlestickData { return &c.Bid }},
+	{"ask", [4]CandleColumn{ColumnAskOpen, ColumnAskHigh, ColumnAskLow, ColumnAskClose}, func(c *Candlestick) **CandlestickData { return &c.Ask }},
+	{"mid", [4]CandleColumn{ColumnMidOpen, ColumnMidHigh, ColumnMidLow, ColumnMidClose}, func(c *Candlestick) **CandlestickData { return &c.Mid }},
+}
+
+// CandleCSVOptions configure the CSV export and import of candlesticks
+type CandleCSVOptions struct {
+	// The columns to export. Default: the time, the open, high, low and close of each of the bid, ask and mid data
+	// any of the candlesticks has, the volume and the complete flag. The import reads the columns of the header.
+	Columns []CandleColumn
+
+	// The layout of the time column. Default: time.RFC3339Nano
+	TimeFormat string
+}
+
+func (o CandleCSVOptions) timeFormat() string {
+	if o.TimeFormat == "" {
+		return time.RFC3339Nano
+	}
+	return o.TimeFormat
+}
+
+// WriteCandlesCSV writes the candlesticks as CSV with a header row. The prices are written with all of their digits,
+// and the cells of the bid, ask or mid data a candlestick does not have are left empty.
+func WriteCandlesCSV(w io.Writer, candles []Candlestick, options CandleCSVOptions) error {
+	columns := options.Columns
+	if len(columns) == 0 {
+		columns = defaultCandleColumns(candles)
+	}
+	writer := csv.NewWriter(w)
+	header := make([]string, len(columns))
+	for i, column := range columns {
+		header[i] = string(column)
+	}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	row := make([]string, len(columns))
+	for _, candle := range candles {
+		for i, column := range columns {
+			cell, err := candleCell(candle, column, options.timeFormat())
+			if err != nil {
+				return err
+			}
+			row[i] = cell
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// ReadCandlesCSV reads candlesticks written by WriteCandlesCSV, or any CSV whose header names CandleColumns. Other
+// columns are ignored. The time column is required, and the bid, ask or mid data is set when its cells are not empty.
+func ReadCandlesCSV(r io.Reader, options CandleCSVOptions) ([]Candlestick, error) {
+	reader := csv.NewReader(r)
+	header, err := reader.Read()
+	if err != nil {
+		return nil, err
+	}
+	indexes := make(map[CandleColumn]int, len(header))
+	for i, name := range header {
+		indexes[CandleColumn(name)] = i
+	}
+	if _, ok := indexes[ColumnTime]; !ok {
+		return nil, fmt.Errorf("missing %s column", ColumnTime)
+	}
+	var candles []Candlestick
+	for line := 2; ; line++ {
+		row, err := reader.Read()
+		if err == io.EOF {
+			return candles, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		candle, err := parseCandleRow(row, indexes, options.timeFormat())
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		candles = append(candles, candle)
+	}
+}
+
+// WriteCandlesJSONL writes the candlesticks as JSON Lines, one candlestick per line in the format of the v20 REST API
+func WriteCandlesJSONL(w io.Writer, candles []Candlestick) error {
+	encoder := json.NewEncoder(w)
+	for _, candle := range candles {
+		if err := encoder.Encode(candle); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadCandlesJSONL reads candlesticks written by WriteCandlesJSONL
+func ReadCandlesJSONL(r io.Reader) ([]Candlestick, error) {
+	var candles []Candlestick
+	decoder := json.NewDecoder(r)
+	for decoder.More() {
+		var candle Candlestick
+		if err := decoder.Decode(&candle); err != nil {
+			return nil, err
+		}
+		candles = append(candles, candle)
+	}
+	return candles, nil
+}
+
+// ImportCandles saves imported candlesticks of the series, ordered by time, into the store. The candlesticks are
+// assumed to be the complete series between the first and the last one (as exported from a CandleCache or
+// GetInstrumentCandles with the alignment), so that range is recorded as covered. Only the candlesticks before the
+// first incomplete one are saved, as the others may still change.
+func ImportCandles(store CandleStore, key CandleKey, alignment CandleAlignment, candles []Candlestick) error {
+	aligner, err := NewCandleAligner(key.Granularity, alignment)
+	if err != nil {
+		return err
+	}
+	complete := 0
+	for complete < len(candles) && candles[complete].Complete {
+		complete++
+	}
+	if complete == 0 {
+		return nil
+	}
+	covered := TimeRange{From: candles[0].Time, To: aligner.Period(candles[complete-1].Time).To}
+	return store.Save(key, covered, candles[:complete])
+}
+
+// defaultCandleColumns returns the columns of the data the candlesticks have
+func defaultCandleColumns(candles []Candlestick) []CandleColumn {
+	columns := []CandleColumn{ColumnTime}
+	for _, component := range candleComponents {
+		for i := range candles {
+			if *component.data(&candles[i]) != nil {
+				columns = append(columns, component.columns[:]...)
+				break
+			}
+		}
+	}
+	return append(columns, ColumnVolume, ColumnComplete)
+}
+
+// candleCell formats the column of the candlestick
+func candleCell(candle Candlestick, column CandleColumn, timeFormat string) (string, error) {
+	switch column {
+	case ColumnTime:
+		return candle.Time.UTC().Format(timeFormat), nil
+	case ColumnVolume:
+		return strconv.Itoa(candle.Volume), nil
+	case ColumnComplete:
+		return strconv.FormatBool(candle.Complete), nil
+	}
+	for _, component := range candleComponents {
+		for i, c := range component.columns {
+			if c != column {
+				continue
+			}
+			data := *component.data(&candle)
+			if data == nil {
+				return "", nil
+			}
+			return [4]decimal.Decimal{data.Open, data.High, data.Low, data.Close}[i].String(), nil
+		}
+	}
+	return "", fmt.Errorf("unknown column %s", column)
+}
+
+// parseCandleRow parses a CSV row with the columns at the indexes
+func parseCandleRow(row []string, indexes map[CandleColumn]int, timeFormat string) (Candlestick, error) {
+	cell := func(column CandleColumn) string {
+		if i, ok := indexes[column]; ok && i < len(row) {
+			return row[i]
+		}
+		return ""
+	}
+	var candle Candlestick
+	var err error
+	if candle.Time, err = time.Parse(timeFormat, cell(ColumnTime)); err != nil {
+		return Candlestick{}, err
+	}
+	if volume := cell(ColumnVolume); volume != "" {
+		if candle.Volume, err = strconv.Atoi(volume); err != nil {
+			return Candlestick{}, err
+		}
+	}
+	if complete := cell(ColumnComplete); complete != "" {
+		if candle.Complete, err = strconv.ParseBool(complete); err != nil {
+			return Candlestick{}, err
+		}
+	}
+	for _, component := range candleComponents {
+		var prices [4]decimal.Decimal
+		empty := 0
+		for i, column := range component.columns {
+			value := cell(column)
+			if value == "" {
+				empty++
+				continue
+			}
+			if prices[i], err = decimal.NewFromString(value); err != nil {
+				return Candlestick{}, fmt.Errorf("%s: %w", column, err)
+			}
+		}
+		switch empty {
+		case len(prices):
+		case 0:
+			*component.data(&candle) = &CandlestickData{Open: prices[0], High: prices[1], Low: prices[2], Close: prices[3]}
+		default:
+			return Candlestick{}, fmt.Errorf("incomplete %s data", component.name)
+		}
+	}
+	return candle, nil
+}
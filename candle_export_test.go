@@ -0,0 +1,149 @@
+package oanda_sdk
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func exportCandles() []Candlestick {
+	return []Candlestick{
+		{
+			Time:     candleStoreStart,
+			Bid:      &CandlestickData{Open: decimal.RequireFromString("1.10001"), High: decimal.RequireFromString("1.10102"), Low: decimal.RequireFromString("1.09987"), Close: decimal.RequireFromString("1.10050")},
+			Ask:      &CandlestickData{Open: decimal.RequireFromString("1.10011"), High: decimal.RequireFromString("1.10112"), Low: decimal.RequireFromString("1.09997"), Close: decimal.RequireFromString("1.10060")},
+			Volume:   42,
+			Complete: true,
+		},
+		{
+			Time:   candleStoreStart.Add(time.Minute),
+			Bid:    &CandlestickData{Open: decimal.RequireFromString("1.10050"), High: decimal.RequireFromString("1.1006"), Low: decimal.RequireFromString("1.1004"), Close: decimal.RequireFromString("1.100412345")},
+			Volume: 3,
+		},
+	}
+}
+
+// sameCandles compares the candlesticks by value
+func sameCandles(a, b []Candlestick) bool {
+	sameData := func(x, y *CandlestickData) bool {
+		if x == nil || y == nil {
+			return x == y
+		}
+		return x.Open.Equal(y.Open) && x.High.Equal(y.High) && x.Low.Equal(y.Low) && x.Close.Equal(y.Close)
+	}
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].Time.Equal(b[i].Time) || a[i].Volume != b[i].Volume || a[i].Complete != b[i].Complete ||
+			!sameData(a[i].Bid, b[i].Bid) || !sameData(a[i].Ask, b[i].Ask) || !sameData(a[i].Mid, b[i].Mid) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestCandlesCSVRoundTrip(t *testing.T) {
+	var buffer bytes.Buffer
+	if err := WriteCandlesCSV(&buffer, exportCandles(), CandleCSVOptions{}); err != nil {
+		t.Fatal("Got ", err)
+	}
+	lines := strings.Split(buffer.String(), "\n")
+	if lines[0] != "time,bid_open,bid_high,bid_low,bid_close,ask_open,ask_high,ask_low,ask_close,volume,complete" {
+		t.Error("Got ", lines[0])
+	}
+	if lines[2] != "2024-01-02T00:01:00Z,1.1005,1.1006,1.1004,1.100412345,,,,,3,false" {
+		t.Error("Got ", lines[2])
+	}
+	candles, err := ReadCandlesCSV(&buffer, CandleCSVOptions{})
+	if err != nil {
+		t.Fatal("Got ", err)
+	}
+	if !sameCandles(candles, exportCandles()) {
+		t.Error("Got ", candles)
+	}
+	if candles[1].Ask != nil || candles[1].Mid != nil || !candles[1].Bid.Close.Equal(decimal.RequireFromString("1.100412345")) {
+		t.Error("Got ", candles[1])
+	}
+}
+
+func TestCandlesCSVColumns(t *testing.T) {
+	var buffer bytes.Buffer
+	options := CandleCSVOptions{Columns: []CandleColumn{ColumnTime, ColumnBidClose}, TimeFormat: time.DateTime}
+	if err := WriteCandlesCSV(&buffer, exportCandles(), options); err != nil {
+		t.Fatal("Got ", err)
+	}
+	if buffer.String() != "time,bid_close\n2024-01-02 00:00:00,1.1005\n2024-01-02 00:01:00,1.100412345\n" {
+		t.Error("Got ", buffer.String())
+	}
+	// A single close is not enough for the bid data
+	if _, err := ReadCandlesCSV(&buffer, options); err == nil {
+		t.Error("Got no error for incomplete bid data")
+	}
+
+	candles, err := ReadCandlesCSV(strings.NewReader("time,note,mid_open,mid_high,mid_low,mid_close\n2024-01-02 00:00:00,x,1,2,0.5,1.5\n"), options)
+	if err != nil {
+		t.Fatal("Got ", err)
+	}
+	if len(candles) != 1 || !candles[0].Time.Equal(candleStoreStart) || !candles[0].Mid.Low.Equal(decimal.RequireFromString("0.5")) {
+		t.Error("Got ", candles)
+	}
+}
+
+func TestCandlesJSONLRoundTrip(t *testing.T) {
+	var buffer bytes.Buffer
+	if err := WriteCandlesJSONL(&buffer, exportCandles()); err != nil {
+		t.Fatal("Got ", err)
+	}
+	if strings.Count(buffer.String(), "\n") != 2 {
+		t.Error("Got ", buffer.String())
+	}
+	candles, err := ReadCandlesJSONL(&buffer)
+	if err != nil {
+		t.Fatal("Got ", err)
+	}
+	if !sameCandles(candles, exportCandles()) {
+		t.Error("Got ", candles)
+	}
+}
+
+func TestImportCandles(t *testing.T) {
+	store := NewMemoryCandleStore()
+	key := CandleKey{Instrument: "EUR_USD", Granularity: M1, Price: "BA"}
+	if err := ImportCandles(store, key, CandleAlignment{}, exportCandles()); err != nil {
+		t.Fatal("Got ", err)
+	}
+	covered, err := store.Covered(key)
+	if err != nil {
+		t.Fatal("Got ", err)
+	}
+	if fmt.Sprint(covered) != fmt.Sprint([]TimeRange{minutes(0, 1)}) {
+		t.Error("Got ", covered)
+	}
+	// The incomplete candlestick is not stored
+	candles, err := store.Load(key, minutes(0, 2))
+	if err != nil || len(candles) != 1 {
+		t.Error("Got ", candles, err)
+	}
+}
+
+func TestImportCandlesWithAlignment(t *testing.T) {
+	store := NewMemoryCandleStore()
+	key := CandleKey{Instrument: "EUR_USD", Granularity: D, Price: "M"}
+	// Daily candlesticks aligned to midnight UTC rather than to 17:00 in New York
+	start := time.Date(2024, 6, 3, 0, 0, 0, 0, time.UTC)
+	candles := []Candlestick{{Time: start, Complete: true}, {Time: start.Add(24 * time.Hour), Complete: true}}
+	hour, timezone := 0, "UTC"
+	alignment := CandleAlignment{DailyAlignment: &hour, AlignmentTimezone: &timezone}
+	if err := ImportCandles(store, key, alignment, candles); err != nil {
+		t.Fatal("Got ", err)
+	}
+	covered, err := store.Covered(key)
+	if err != nil || fmt.Sprint(covered) != fmt.Sprint([]TimeRange{{From: start, To: start.Add(48 * time.Hour)}}) {
+		t.Error("Got ", covered, err)
+	}
+}
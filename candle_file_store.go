@@ -0,0 +1,133 @@
+package oanda_sdk
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// FileCandleStore is a CandleStore keeping each series of candlesticks in a gzip compressed JSON Lines file in a
+// directory, one candlestick per line. The time ranges covered by a series are kept in a JSON file next to it.
+//
+// Saving rewrites the whole file of the series, the files are replaced atomically.
+type FileCandleStore struct {
+	dir string
+	mu  sync.RWMutex
+}
+
+// NewFileCandleStore creates a FileCandleStore in the directory, which is created if it does not exist
+func NewFileCandleStore(dir string) (*FileCandleStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &FileCandleStore{dir: dir}, nil
+}
+
+func (s *FileCandleStore) path(key CandleKey, extension string) string {
+	return filepath.Join(s.dir, fmt.Sprintf("%s-%s-%s%s", key.Instrument, key.Granularity, key.price(), extension))
+}
+
+// Load returns the stored candlesticks of the series starting within the time range
+func (s *FileCandleStore) Load(key CandleKey, within TimeRange) ([]Candlestick, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	candles, err := s.readCandles(key)
+	if err != nil {
+		return nil, err
+	}
+	return candlesWithin(candles, within), nil
+}
+
+// Save stores the candlesticks fetched for the time range
+func (s *FileCandleStore) Save(key CandleKey, covered TimeRange, candles []Candlestick) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored, err := s.readCandles(key)
+	if err != nil {
+		return err
+	}
+	ranges, err := s.readCovered(key)
+	if err != nil {
+		return err
+	}
+	if err := s.writeCandles(key, mergeCandles(stored, candles)); err != nil {
+		return err
+	}
+	// The coverage is written last, so that a failed save never claims candlesticks which were not written
+	return writeFileAtomically(s.path(key, ".covered.json"), func(f *os.File) error {
+		return json.NewEncoder(f).Encode(mergeRanges(ranges, covered))
+	})
+}
+
+// Covered returns the time ranges of the series covered by the saved candlesticks
+func (s *FileCandleStore) Covered(key CandleKey) ([]TimeRange, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.readCovered(key)
+}
+
+func (s *FileCandleStore) readCovered(key CandleKey) ([]TimeRange, error) {
+	data, err := os.ReadFile(s.path(key, ".covered.json"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var ranges []TimeRange
+	return ranges, json.Unmarshal(data, &ranges)
+}
+
+func (s *FileCandleStore) readCandles(key CandleKey) ([]Candlestick, error) {
+	f, err := os.Open(s.path(key, ".jsonl.gz"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	reader, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return ReadCandlesJSONL(reader)
+}
+
+func (s *FileCandleStore) writeCandles(key CandleKey, candles []Candlestick) error {
+	return writeFileAtomically(s.path(key, ".jsonl.gz"), func(f *os.File) error {
+		buffered := bufio.NewWriter(f)
+		writer := gzip.NewWriter(buffered)
+		if err := WriteCandlesJSONL(writer, candles); err != nil {
+			return err
+		}
+		if err := writer.Close(); err != nil {
+			return err
+		}
+		return buffered.Flush()
+	})
+}
+
+// writeFileAtomically writes the file through a temporary file renamed over it once completely written
+func writeFileAtomically(path string, write func(f *os.File) error) error {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
@@ -0,0 +1,154 @@
+package oanda_sdk
+
+import (
+	"fmt"
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+// ResampleOptions configure ResampleCandles
+type ResampleOptions struct {
+	// The alignment of the resampled candlesticks.
+	Alignment CandleAlignment
+
+	// Fill the periods without any candlestick, e.g. over weekends and holidays, with flat candlesticks at the close of
+	// the previous one and no volume. The periods are reported as gaps either way.
+	FillGaps bool
+}
+
+// ResampledCandles are the candlesticks aggregated by ResampleCandles
+type ResampledCandles struct {
+	// The candlesticks of the coarser granularity, ordered by time.
+	Candles []Candlestick
+
+	// The periods between the first and the last candlestick without any candlestick of the finer granularity.
+	Gaps []TimeRange
+}
+
+// ResampleCandles aggregates candlesticks of a finer granularity, ordered by time, into candlesticks of a coarser
+// granularity, e.g. M1 into H4. Each of the bid, ask and mid data is aggregated when all candlesticks of a period have
+// it, and the volumes are summed. A resampled candlestick is complete when all of its candlesticks are and they reach
+// the end of its period.
+func ResampleCandles(candles []Candlestick, from, to CandlestickGranularity, options ResampleOptions) (*ResampledCandles, error) {
+	if from.Duration() == 0 || to.Duration() <= from.Duration() {
+		return nil, fmt.Errorf("cannot resample %s candles to %s", from, to)
+	}
+	source, err := NewCandleAligner(from, options.Alignment)
+	if err != nil {
+		return nil, err
+	}
+	target, err := NewCandleAligner(to, options.Alignment)
+	if err != nil {
+		return nil, err
+	}
+	resampled := &ResampledCandles{}
+	for i := 0; i < len(candles); {
+		period := target.Period(candles[i].Time)
+		j := i
+		for j < len(candles) && candles[j].Time.Before(period.To) {
+			j++
+		}
+		candle := aggregateCandles(period.From, candles[i:j])
+		candle.Complete = candle.Complete && !source.Period(candles[j-1].Time).To.Before(period.To)
+		resampled.Candles = append(resampled.Candles, candle)
+		i = j
+
+		if i == len(candles) {
+			break
+		}
+		for gap := target.Period(period.To); gap.From.Before(target.Period(candles[i].Time).From); gap = target.Period(gap.To) {
+			resampled.Gaps = append(resampled.Gaps, gap)
+			if options.FillGaps {
+				resampled.Candles = append(resampled.Candles, flatCandle(gap.From, candle))
+			}
+		}
+	}
+	return resampled, nil
+}
+
+// aggregateCandles aggregates the candlesticks of a period starting at the time
+func aggregateCandles(start time.Time, candles []Candlestick) Candlestick {
+	aggregated := Candlestick{Time: start, Complete: true}
+	component := func(data func(Candlestick) *CandlestickData) *CandlestickData {
+		var result *CandlestickData
+		for _, candle := range candles {
+			d := data(candle)
+			if d == nil {
+				return nil
+			}
+			if result == nil {
+				copied := *d
+				result = &copied
+				continue
+			}
+			result.High = decimal.Max(result.High, d.High)
+			result.Low = decimal.Min(result.Low, d.Low)
+			result.Close = d.Close
+		}
+		return result
+	}
+	aggregated.Bid = component(func(c Candlestick) *CandlestickData { return c.Bid })
+	aggregated.Ask = component(func(c Candlestick) *CandlestickData { return c.Ask })
+	aggregated.Mid = component(func(c Candlestick) *CandlestickData { return c.Mid })
+	for _, candle := range candles {
+		aggregated.Volume += candle.Volume
+		aggregated.Complete = aggregated.Complete && candle.Complete
+	}
+	return aggregated
+}
+
+// flatCandle creates a complete candlestick without volume at the close of the previous candlestick
+func flatCandle(start time.Time, previous Candlestick) Candlestick {
+	flat := func(data *CandlestickData) *CandlestickData {
+		if data == nil {
+			return nil
+		}
+		return &CandlestickData{Open: data.Close, High: data.Close, Low: data.Close, Close: data.Close}
+	}
+	return Candlestick{Time: start, Bid: flat(previous.Bid), Ask: flat(previous.Ask), Mid: flat(previous.Mid), Complete: true}
+}
+
+// Data returns the candlestick data of a single Price component: "M", "B" or "A". The mid data is derived from the
+// bid and ask data when the candlestick has no mid data (see MidCandleData). The second return value is false when
+// the data is not available.
+func (c Candlestick) Data(component PricingComponent) (CandlestickData, bool) {
+	var data *CandlestickData
+	switch component {
+	case "M":
+		data = c.Mid
+		if data == nil && c.Bid != nil && c.Ask != nil {
+			mid := MidCandleData(*c.Bid, *c.Ask)
+			data = &mid
+		}
+	case "B":
+		data = c.Bid
+	case "A":
+		data = c.Ask
+	}
+	if data == nil {
+		return CandlestickData{}, false
+	}
+	return *data, true
+}
+
+// MidCandleData derives mid candlestick data from bid and ask candlestick data. The open and close are exact, the
+// high and low are approximated by the midpoint of the bid and ask highs and lows, which may have occurred at
+// different times.
+func MidCandleData(bid, ask CandlestickData) CandlestickData {
+	two := decimal.NewFromInt(2)
+	return CandlestickData{
+		Open:  bid.Open.Add(ask.Open).Div(two),
+		High:  bid.High.Add(ask.High).Div(two),
+		Low:   bid.Low.Add(ask.Low).Div(two),
+		Close: bid.Close.Add(ask.Close).Div(two),
+	}
+}
+
+// BidAskCandleData derives bid and ask candlestick data from mid candlestick data, assuming a constant spread
+func BidAskCandleData(mid CandlestickData, spread decimal.Decimal) (bid, ask CandlestickData) {
+	half := spread.Div(decimal.NewFromInt(2))
+	shift := func(offset decimal.Decimal) CandlestickData {
+		return CandlestickData{Open: mid.Open.Add(offset), High: mid.High.Add(offset), Low: mid.Low.Add(offset), Close: mid.Close.Add(offset)}
+	}
+	return shift(half.Neg()), shift(half)
+}
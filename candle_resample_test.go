@@ -0,0 +1,75 @@
+package oanda_sdk
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestResampleCandlesAggregatesAndFillsGaps(t *testing.T) {
+	d := decimal.RequireFromString
+	start := time.Date(2024, 1, 10, 10, 0, 0, 0, time.UTC)
+	candle := func(minute int, open, high, low, close string, complete bool) Candlestick {
+		return Candlestick{
+			Time:     start.Add(time.Duration(minute) * time.Minute),
+			Mid:      &CandlestickData{Open: d(open), High: d(high), Low: d(low), Close: d(close)},
+			Volume:   10,
+			Complete: complete,
+		}
+	}
+	candles := []Candlestick{
+		candle(0, "1.10", "1.12", "1.09", "1.11", true),
+		candle(15, "1.11", "1.15", "1.10", "1.14", true),
+		candle(30, "1.14", "1.14", "1.05", "1.06", true),
+		candle(45, "1.06", "1.08", "1.06", "1.07", true),
+		candle(180, "1.20", "1.21", "1.19", "1.20", true),
+		candle(195, "1.20", "1.22", "1.20", "1.21", false),
+	}
+	resampled, err := ResampleCandles(candles, M15, H1, ResampleOptions{FillGaps: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resampled.Candles) != 4 || len(resampled.Gaps) != 2 {
+		t.Fatal("Got ", resampled)
+	}
+	first := resampled.Candles[0]
+	if !first.Complete || first.Volume != 40 || first.Bid != nil {
+		t.Error("Got ", first)
+	}
+	if !first.Mid.Open.Equal(d("1.10")) || !first.Mid.High.Equal(d("1.15")) || !first.Mid.Low.Equal(d("1.05")) || !first.Mid.Close.Equal(d("1.07")) {
+		t.Error("Got ", first.Mid)
+	}
+	filled := resampled.Candles[1]
+	if !filled.Time.Equal(start.Add(time.Hour)) || filled.Volume != 0 || !filled.Mid.Open.Equal(d("1.07")) || !filled.Mid.High.Equal(d("1.07")) {
+		t.Error("Got ", filled, filled.Mid)
+	}
+	if !resampled.Gaps[1].From.Equal(start.Add(2*time.Hour)) || !resampled.Gaps[1].To.Equal(start.Add(3*time.Hour)) {
+		t.Error("Got ", resampled.Gaps)
+	}
+	last := resampled.Candles[3]
+	if last.Complete || last.Volume != 20 || !last.Mid.Close.Equal(d("1.21")) {
+		t.Error("Got ", last)
+	}
+
+	if _, err := ResampleCandles(candles, H1, M15, ResampleOptions{}); err == nil {
+		t.Error("Expected an error resampling to a finer granularity")
+	}
+}
+
+func TestCandleDataConversions(t *testing.T) {
+	d := decimal.RequireFromString
+	bid := CandlestickData{Open: d("1.0"), High: d("1.4"), Low: d("0.8"), Close: d("1.2")}
+	ask := CandlestickData{Open: d("1.2"), High: d("1.6"), Low: d("1.0"), Close: d("1.4")}
+	mid, ok := Candlestick{Bid: &bid, Ask: &ask}.Data("M")
+	if !ok || !mid.Open.Equal(d("1.1")) || !mid.Low.Equal(d("0.9")) || !mid.Close.Equal(d("1.3")) {
+		t.Error("Got ", mid)
+	}
+	derivedBid, derivedAsk := BidAskCandleData(mid, d("0.2"))
+	if !derivedBid.Open.Equal(bid.Open) || !derivedAsk.High.Equal(ask.High) {
+		t.Error("Got ", derivedBid, derivedAsk)
+	}
+	if _, ok := (Candlestick{Bid: &bid}).Data("A"); ok {
+		t.Error("Expected no ask data")
+	}
+}
@@ -0,0 +1,131 @@
+package oanda_sdk
+
+import (
+	"database/sql"
+	"encoding/json"
+)
+
+// SQLCandleStore is a CandleStore keeping the candlesticks in an SQLite database. The SDK does not depend on an
+// SQLite driver, the database is opened by the caller with the driver of their choice (e.g. mattn/go-sqlite3 or
+// modernc.org/sqlite).
+//
+// Each candlestick is kept as a JSON document in a row keyed by its series and time, the times are stored as Unix
+// nanoseconds.
+type SQLCandleStore struct {
+	db *sql.DB
+}
+
+// NewSQLCandleStore creates an SQLCandleStore in the database, creating its tables if they do not exist
+func NewSQLCandleStore(db *sql.DB) (*SQLCandleStore, error) {
+	for _, statement := range []string{
+		`CREATE TABLE IF NOT EXISTS candles (
+			instrument TEXT NOT NULL,
+			granularity TEXT NOT NULL,
+			price TEXT NOT NULL,
+			time INTEGER NOT NULL,
+			candle TEXT NOT NULL,
+			PRIMARY KEY (instrument, granularity, price, time)
+		)`,
+		`CREATE TABLE IF NOT EXISTS candles_covered (
+			instrument TEXT NOT NULL,
+			granularity TEXT NOT NULL,
+			price TEXT NOT NULL,
+			covered TEXT NOT NULL,
+			PRIMARY KEY (instrument, granularity, price)
+		)`,
+	} {
+		if _, err := db.Exec(statement); err != nil {
+			return nil, err
+		}
+	}
+	return &SQLCandleStore{db: db}, nil
+}
+
+// Load returns the stored candlesticks of the series starting within the time range
+func (s *SQLCandleStore) Load(key CandleKey, within TimeRange) ([]Candlestick, error) {
+	rows, err := s.db.Query(
+		`SELECT candle FROM candles WHERE instrument = ? AND granularity = ? AND price = ? AND time >= ? AND time < ? ORDER BY time`,
+		key.Instrument, string(key.Granularity), string(key.price()), within.From.UnixNano(), within.To.UnixNano(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var candles []Candlestick
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+		var candle Candlestick
+		if err := json.Unmarshal([]byte(data), &candle); err != nil {
+			return nil, err
+		}
+		candles = append(candles, candle)
+	}
+	return candles, rows.Err()
+}
+
+// Save stores the candlesticks fetched for the time range in a single transaction
+func (s *SQLCandleStore) Save(key CandleKey, covered TimeRange, candles []Candlestick) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for _, candle := range candles {
+		data, err := json.Marshal(candle)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(
+			`INSERT OR REPLACE INTO candles (instrument, granularity, price, time, candle) VALUES (?, ?, ?, ?, ?)`,
+			key.Instrument, string(key.Granularity), string(key.price()), candle.Time.UnixNano(), string(data),
+		)
+		if err != nil {
+			return err
+		}
+	}
+	ranges, err := s.covered(tx, key)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(mergeRanges(ranges, covered))
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(
+		`INSERT OR REPLACE INTO candles_covered (instrument, granularity, price, covered) VALUES (?, ?, ?, ?)`,
+		key.Instrument, string(key.Granularity), string(key.price()), string(data),
+	)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Covered returns the time ranges of the series covered by the saved candlesticks
+func (s *SQLCandleStore) Covered(key CandleKey) ([]TimeRange, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	return s.covered(tx, key)
+}
+
+func (s *SQLCandleStore) covered(tx *sql.Tx, key CandleKey) ([]TimeRange, error) {
+	var data string
+	err := tx.QueryRow(
+		`SELECT covered FROM candles_covered WHERE instrument = ? AND granularity = ? AND price = ?`,
+		key.Instrument, string(key.Granularity), string(key.price()),
+	).Scan(&data)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var ranges []TimeRange
+	return ranges, json.Unmarshal([]byte(data), &ranges)
+}
@@ -0,0 +1,227 @@
+package oanda_sdk
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+// fakeSQL is a database/sql driver keeping the rows written by SQLCandleStore in memory, the writes of a transaction
+// being applied when it is committed. It tells the statements of the store apart by the tables they name rather than
+// parsing them, so the SQL dialect of the store (e.g. INSERT OR REPLACE and the table definitions) is not verified
+// against SQLite by these tests.
+var fakeSQL = &fakeSQLDriver{databases: make(map[string]fakeSQLTables)}
+
+func init() {
+	sql.Register("fakesql", fakeSQL)
+}
+
+// fakeSQLKey is the primary key of a row: the series, and the time of a candlestick
+type fakeSQLKey struct {
+	table  string
+	series [3]string
+	time   int64
+}
+
+type fakeSQLTables map[fakeSQLKey]string
+
+type fakeSQLDriver struct {
+	mu        sync.Mutex
+	databases map[string]fakeSQLTables
+
+	// Makes the writes to the table fail
+	failTable string
+}
+
+func (d *fakeSQLDriver) Open(name string) (driver.Conn, error) {
+	return &fakeSQLConn{driver: d, name: name}, nil
+}
+
+type fakeSQLConn struct {
+	driver *fakeSQLDriver
+	name   string
+	tx     fakeSQLTables
+}
+
+func (c *fakeSQLConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeSQLStmt{conn: c, query: query}, nil
+}
+
+func (c *fakeSQLConn) Close() error {
+	return nil
+}
+
+func (c *fakeSQLConn) Begin() (driver.Tx, error) {
+	c.driver.mu.Lock()
+	defer c.driver.mu.Unlock()
+	c.tx = maps.Clone(c.driver.databases[c.name])
+	if c.tx == nil {
+		c.tx = make(fakeSQLTables)
+	}
+	return c, nil
+}
+
+func (c *fakeSQLConn) Commit() error {
+	c.driver.mu.Lock()
+	defer c.driver.mu.Unlock()
+	c.driver.databases[c.name], c.tx = c.tx, nil
+	return nil
+}
+
+func (c *fakeSQLConn) Rollback() error {
+	c.tx = nil
+	return nil
+}
+
+type fakeSQLStmt struct {
+	conn  *fakeSQLConn
+	query string
+}
+
+func (s *fakeSQLStmt) Close() error {
+	return nil
+}
+
+func (s *fakeSQLStmt) NumInput() int {
+	return -1
+}
+
+// key returns the key of the row of the table with the arguments starting with its series
+func (s *fakeSQLStmt) key(table string, args []driver.Value) fakeSQLKey {
+	key := fakeSQLKey{table: table, series: [3]string{args[0].(string), args[1].(string), args[2].(string)}}
+	if table == "candles" && len(args) > 4 {
+		key.time = args[3].(int64)
+	}
+	return key
+}
+
+func (s *fakeSQLStmt) Exec(args []driver.Value) (driver.Result, error) {
+	if !strings.HasPrefix(s.query, "INSERT") {
+		// The tables are created on the first write
+		return driver.RowsAffected(0), nil
+	}
+	table := "candles"
+	if strings.Contains(s.query, "candles_covered") {
+		table = "candles_covered"
+	}
+	if table == s.conn.driver.failTable {
+		return nil, errors.New("disk I/O error")
+	}
+	s.conn.tx[s.key(table, args)] = args[len(args)-1].(string)
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeSQLStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.conn.driver.mu.Lock()
+	defer s.conn.driver.mu.Unlock()
+	tables := s.conn.tx
+	if tables == nil {
+		tables = s.conn.driver.databases[s.conn.name]
+	}
+	if strings.Contains(s.query, "candles_covered") {
+		value, ok := tables[s.key("candles_covered", args)]
+		if !ok {
+			return &fakeSQLRows{}, nil
+		}
+		return &fakeSQLRows{values: []string{value}}, nil
+	}
+	series := s.key("candles", args).series
+	var keys []fakeSQLKey
+	for key := range tables {
+		if key.table == "candles" && key.series == series && key.time >= args[3].(int64) && key.time < args[4].(int64) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].time < keys[j].time })
+	rows := &fakeSQLRows{}
+	for _, key := range keys {
+		rows.values = append(rows.values, tables[key])
+	}
+	return rows, nil
+}
+
+type fakeSQLRows struct {
+	values []string
+}
+
+func (r *fakeSQLRows) Columns() []string {
+	return []string{"value"}
+}
+
+func (r *fakeSQLRows) Close() error {
+	return nil
+}
+
+func (r *fakeSQLRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	dest[0], r.values = r.values[0], r.values[1:]
+	return nil
+}
+
+func TestSQLCandleStore(t *testing.T) {
+	db, err := sql.Open("fakesql", t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	store, err := NewSQLCandleStore(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := CandleKey{Instrument: "EUR_USD", Granularity: M1, Price: "BA"}
+	candle := func(minute int, close string) Candlestick {
+		return Candlestick{Time: minutes(minute, minute).From, Complete: true, Bid: &CandlestickData{Close: decimal.RequireFromString(close)}}
+	}
+	if err := store.Save(key, minutes(0, 3), []Candlestick{candle(0, "1.1"), candle(1, "1.2"), candle(2, "1.3")}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Save(key, minutes(2, 4), []Candlestick{candle(2, "1.4"), candle(3, "1.5")}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Save(key, minutes(6, 8), []Candlestick{candle(6, "1.6")}); err != nil {
+		t.Fatal(err)
+	}
+
+	candles, err := store.Load(key, minutes(1, 7))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var closes []string
+	for _, c := range candles {
+		closes = append(closes, c.Bid.Close.String())
+	}
+	if fmt.Sprint(closes) != "[1.2 1.4 1.5 1.6]" {
+		t.Error("Got ", closes)
+	}
+	covered, err := store.Covered(key)
+	if err != nil || fmt.Sprint(covered) != fmt.Sprint([]TimeRange{minutes(0, 4), minutes(6, 8)}) {
+		t.Error("Got ", covered, err)
+	}
+	if candles, _ := store.Load(CandleKey{Instrument: "EUR_USD", Granularity: M1}, minutes(0, 10)); len(candles) != 0 {
+		t.Error("Got ", candles)
+	}
+
+	// A failed Save leaves neither the candlesticks nor the covered range behind
+	fakeSQL.failTable = "candles_covered"
+	defer func() { fakeSQL.failTable = "" }()
+	if err := store.Save(key, minutes(4, 6), []Candlestick{candle(4, "1.7"), candle(5, "1.8")}); err == nil {
+		t.Error("Expected an error")
+	}
+	if candles, _ := store.Load(key, minutes(4, 6)); len(candles) != 0 {
+		t.Error("Got ", candles)
+	}
+	if covered, _ := store.Covered(key); len(covered) != 2 {
+		t.Error("Got ", covered)
+	}
+}
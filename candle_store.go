@@ -0,0 +1,229 @@
+package oanda_sdk
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// CandleKey identifies a series of candlesticks
+type CandleKey struct {
+	// The instrument of the candlesticks.
+	Instrument string
+
+	// The granularity of the candlesticks.
+	Granularity CandlestickGranularity
+
+	// The Price component(s) of the candlesticks. Default: M
+	Price PricingComponent
+}
+
+func (ck CandleKey) price() PricingComponent {
+	if ck.Price == "" {
+		return "M"
+	}
+	return ck.Price
+}
+
+// TimeRange is the range of time from From (inclusive) to To (exclusive)
+type TimeRange struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+// CandleStore persists series of candlesticks together with the time ranges they were fetched for, so that only the
+// missing ranges need to be fetched again (see CandleCache)
+type CandleStore interface {
+	// Load returns the stored candlesticks of the series starting within the time range, ordered by time.
+	Load(key CandleKey, within TimeRange) ([]Candlestick, error)
+
+	// Save stores the candlesticks fetched for the time range, replacing the stored candlesticks with the same time,
+	// and records the time range as covered.
+	Save(key CandleKey, covered TimeRange, candles []Candlestick) error
+
+	// Covered returns the time ranges of the series covered by the saved candlesticks, ordered and merged.
+	Covered(key CandleKey) ([]TimeRange, error)
+}
+
+// mergeCandles merges the candlesticks into the ordered candlesticks, replacing the ones with the same time
+func mergeCandles(candles, updates []Candlestick) []Candlestick {
+	byTime := make(map[time.Time]Candlestick, len(candles)+len(updates))
+	for _, candle := range candles {
+		byTime[candle.Time.UTC()] = candle
+	}
+	for _, candle := range updates {
+		byTime[candle.Time.UTC()] = candle
+	}
+	merged := make([]Candlestick, 0, len(byTime))
+	for _, candle := range byTime {
+		merged = append(merged, candle)
+	}
+	sort.Slice(merged, func(i, j int) bool {
+		return merged[i].Time.Before(merged[j].Time)
+	})
+	return merged
+}
+
+// candlesWithin returns the ordered candlesticks starting within the time range
+func candlesWithin(candles []Candlestick, within TimeRange) []Candlestick {
+	start := sort.Search(len(candles), func(i int) bool {
+		return !candles[i].Time.Before(within.From)
+	})
+	end := sort.Search(len(candles), func(i int) bool {
+		return !candles[i].Time.Before(within.To)
+	})
+	return append([]Candlestick{}, candles[start:max(start, end)]...)
+}
+
+// mergeRanges adds the time range to the ordered time ranges, merging the overlapping and adjacent ones
+func mergeRanges(ranges []TimeRange, add TimeRange) []TimeRange {
+	if !add.From.Before(add.To) {
+		return ranges
+	}
+	all := append(append([]TimeRange{}, ranges...), add)
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].From.Before(all[j].From)
+	})
+	merged := []TimeRange{all[0]}
+	for _, r := range all[1:] {
+		last := &merged[len(merged)-1]
+		if r.From.After(last.To) {
+			merged = append(merged, r)
+		} else if r.To.After(last.To) {
+			last.To = r.To
+		}
+	}
+	return merged
+}
+
+// missingRanges returns the parts of the time range not covered by the ordered time ranges
+func missingRanges(covered []TimeRange, want TimeRange) []TimeRange {
+	var missing []TimeRange
+	from := want.From
+	for _, r := range covered {
+		if !r.To.After(from) {
+			continue
+		}
+		if !r.From.Before(want.To) {
+			break
+		}
+		if r.From.After(from) {
+			missing = append(missing, TimeRange{From: from, To: r.From})
+		}
+		from = r.To
+	}
+	if from.Before(want.To) {
+		missing = append(missing, TimeRange{From: from, To: want.To})
+	}
+	return missing
+}
+
+// MemoryCandleStore is a CandleStore keeping the candlesticks in memory
+type MemoryCandleStore struct {
+	mu     sync.RWMutex
+	series map[CandleKey]*memoryCandleSeries
+}
+
+type memoryCandleSeries struct {
+	candles []Candlestick
+	covered []TimeRange
+}
+
+// NewMemoryCandleStore creates an empty MemoryCandleStore
+func NewMemoryCandleStore() *MemoryCandleStore {
+	return &MemoryCandleStore{series: make(map[CandleKey]*memoryCandleSeries)}
+}
+
+// Load returns the stored candlesticks of the series starting within the time range
+func (s *MemoryCandleStore) Load(key CandleKey, within TimeRange) ([]Candlestick, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	series, ok := s.series[key]
+	if !ok {
+		return nil, nil
+	}
+	return candlesWithin(series.candles, within), nil
+}
+
+// Save stores the candlesticks fetched for the time range
+func (s *MemoryCandleStore) Save(key CandleKey, covered TimeRange, candles []Candlestick) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	series, ok := s.series[key]
+	if !ok {
+		series = &memoryCandleSeries{}
+		s.series[key] = series
+	}
+	series.candles = mergeCandles(series.candles, candles)
+	series.covered = mergeRanges(series.covered, covered)
+	return nil
+}
+
+// Covered returns the time ranges of the series covered by the saved candlesticks
+func (s *MemoryCandleStore) Covered(key CandleKey) ([]TimeRange, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	series, ok := s.series[key]
+	if !ok {
+		return nil, nil
+	}
+	return append([]TimeRange{}, series.covered...), nil
+}
+
+// CandleCache serves candlesticks from a CandleStore, fetching only the ranges missing from the store
+type CandleCache struct {
+	client *Client
+	store  CandleStore
+}
+
+// NewCandleCache creates a CandleCache fetching the missing candlesticks with the client
+func NewCandleCache(client *Client, store CandleStore) *CandleCache {
+	return &CandleCache{client: client, store: store}
+}
+
+// Candles returns the candlesticks of the series starting between from and to, ordered by time. The ranges not
+// covered by the store are fetched (see FetchCandles) and saved first. A range ending in the future is only fetched
+// up to now.
+//
+// Incomplete candlesticks are saved, but their time range is not recorded as covered, so they are fetched again by
+// the next call until they are complete.
+func (cc *CandleCache) Candles(key CandleKey, from, to time.Time) ([]Candlestick, error) {
+	want := TimeRange{From: from, To: to}
+	if now := time.Now(); want.To.After(now) {
+		want.To = now
+	}
+	covered, err := cc.store.Covered(key)
+	if err != nil {
+		return nil, err
+	}
+	for _, missing := range missingRanges(covered, want) {
+		if err := cc.fetch(key, missing); err != nil {
+			return nil, err
+		}
+	}
+	return cc.store.Load(key, TimeRange{From: from, To: to})
+}
+
+// fetch fetches and saves the candlesticks of the time range
+func (cc *CandleCache) fetch(key CandleKey, missing TimeRange) error {
+	granularity, price := key.Granularity, key.price()
+	candles, err := cc.client.FetchCandles(key.Instrument, GetInstrumentCandlesRequest{
+		Price:       &price,
+		Granularity: &granularity,
+		From:        &missing.From,
+		To:          &missing.To,
+	})
+	if err != nil {
+		return err
+	}
+	covered := missing
+	for _, candle := range candles {
+		if !candle.Complete {
+			if candle.Time.Before(covered.To) {
+				covered.To = candle.Time
+			}
+			break
+		}
+	}
+	return cc.store.Save(key, covered, candles)
+}
@@ -0,0 +1,110 @@
+package oanda_sdk
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+var candleStoreStart = time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+func minutes(from, to int) TimeRange {
+	return TimeRange{From: candleStoreStart.Add(time.Duration(from) * time.Minute), To: candleStoreStart.Add(time.Duration(to) * time.Minute)}
+}
+
+func TestMissingRanges(t *testing.T) {
+	covered := mergeRanges(mergeRanges(mergeRanges(nil, minutes(10, 20)), minutes(30, 40)), minutes(20, 25))
+	if fmt.Sprint(covered) != fmt.Sprint([]TimeRange{minutes(10, 25), minutes(30, 40)}) {
+		t.Error("Got ", covered)
+	}
+	missing := missingRanges(covered, minutes(0, 50))
+	if fmt.Sprint(missing) != fmt.Sprint([]TimeRange{minutes(0, 10), minutes(25, 30), minutes(40, 50)}) {
+		t.Error("Got ", missing)
+	}
+	if missing := missingRanges(covered, minutes(12, 22)); len(missing) != 0 {
+		t.Error("Got ", missing)
+	}
+}
+
+func TestCandleCacheFetchesOnlyMissingRanges(t *testing.T) {
+	var fetched []string
+	incomplete := candleStoreStart.Add(14 * time.Minute)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("price") != "M" || query.Get("granularity") != "M1" {
+			t.Error("Got ", r.URL.RawQuery)
+		}
+		from, _ := time.Parse(time.RFC3339, query.Get("from"))
+		to, _ := time.Parse(time.RFC3339, query.Get("to"))
+		fetched = append(fetched, fmt.Sprint(from.Sub(candleStoreStart).Minutes(), "-", to.Sub(candleStoreStart).Minutes()))
+		var candles []string
+		for at := from; at.Before(to); at = at.Add(time.Minute) {
+			complete := !at.Equal(incomplete)
+			candles = append(candles, fmt.Sprintf(`{"time": "%s", "complete": %t}`, at.Format(time.RFC3339), complete))
+		}
+		fmt.Fprintf(w, `{"candles": [%s]}`, strings.Join(candles, ","))
+	}))
+	defer server.Close()
+	cache := NewCandleCache(NewClient(server.URL, "token", server.Client()), NewMemoryCandleStore())
+	key := CandleKey{Instrument: "EUR_USD", Granularity: M1}
+
+	for _, r := range []TimeRange{minutes(0, 10), minutes(5, 15), minutes(5, 15)} {
+		candles, err := cache.Candles(key, r.From, r.To)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(candles) != 10 || !candles[0].Time.Equal(r.From) {
+			t.Error("Got ", candles)
+		}
+	}
+	// The incomplete candlestick is fetched again
+	if fmt.Sprint(fetched) != "[0-10 10-15 14-15]" {
+		t.Error("Got ", fetched)
+	}
+}
+
+func TestFileCandleStoreRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewFileCandleStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := CandleKey{Instrument: "EUR_USD", Granularity: M1, Price: "BA"}
+	candle := func(minute int, close string) Candlestick {
+		return Candlestick{Time: minutes(minute, minute).From, Complete: true, Bid: &CandlestickData{Close: decimal.RequireFromString(close)}}
+	}
+	if err := store.Save(key, minutes(0, 3), []Candlestick{candle(0, "1.1"), candle(1, "1.2"), candle(2, "1.3")}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Save(key, minutes(2, 4), []Candlestick{candle(2, "1.4"), candle(3, "1.5")}); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := NewFileCandleStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	candles, err := reopened.Load(key, minutes(1, 10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var closes []string
+	for _, c := range candles {
+		closes = append(closes, c.Bid.Close.String())
+	}
+	if fmt.Sprint(closes) != "[1.2 1.4 1.5]" {
+		t.Error("Got ", closes)
+	}
+	covered, err := reopened.Covered(key)
+	if err != nil || len(covered) != 1 || !covered[0].To.Equal(minutes(0, 4).To) {
+		t.Error("Got ", covered, err)
+	}
+	if candles, _ := reopened.Load(CandleKey{Instrument: "EUR_USD", Granularity: M1}, minutes(0, 10)); len(candles) != 0 {
+		t.Error("Got ", candles)
+	}
+}
@@ -0,0 +1,142 @@
+package oanda_sdk
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	// candlePollDelay is how long after the end of a candlestick's period it is polled for, tolerating the drift
+	// between the local clock and OANDA's
+	candlePollDelay = 2 * time.Second
+
+	// candlePollRetryInterval is how long to wait before polling again for a candlestick which is not complete yet
+	candlePollRetryInterval = time.Second
+
+	// candlePollRetries is the number of times a candlestick which is not complete yet is polled for again before
+	// waiting for the end of the next period
+	candlePollRetries = 5
+)
+
+// SubscribeCandles delivers the mid candlesticks of an instrument as they complete (see SubscribeCandlesContext)
+func (c *Client) SubscribeCandles(instrument string, granularity CandlestickGranularity) (<-chan Candlestick, error) {
+	return c.SubscribeCandlesContext(context.Background(), instrument, granularity)
+}
+
+// SubscribeCandlesContext delivers the mid candlesticks of an instrument as they complete, polling
+// GetInstrumentCandles once the period of each candlestick has passed. Each poll asks for the candlesticks after the
+// last completed one (using IncludeFirst=false), so only the newly completed candlesticks are delivered, in order.
+//
+// The polls are delayed a little after the end of each period to tolerate clock drift, and a candlestick which is not
+// complete yet is polled for again shortly after. Failed polls are retried the same way. The channel is closed when
+// the context is done.
+func (c *Client) SubscribeCandlesContext(ctx context.Context, instrument string, granularity CandlestickGranularity) (<-chan Candlestick, error) {
+	aligner, err := NewCandleAligner(granularity, CandleAlignment{})
+	if err != nil {
+		return nil, err
+	}
+	poller := &candlePoller{
+		client:      c,
+		instrument:  instrument,
+		granularity: granularity,
+		aligner:     aligner,
+		now:         time.Now,
+		sleep:       sleepContext,
+	}
+	return poller.subscribe(ctx)
+}
+
+// candlePoller polls the candlesticks of a subscription, reading the time and waiting through its clock functions
+type candlePoller struct {
+	client      *Client
+	instrument  string
+	granularity CandlestickGranularity
+	aligner     *CandleAligner
+
+	// Returns the current time.
+	now func() time.Time
+
+	// Waits for the duration, it is false when the context is done first.
+	sleep func(ctx context.Context, d time.Duration) bool
+}
+
+func (p *candlePoller) subscribe(ctx context.Context) (<-chan Candlestick, error) {
+	// The latest candlestick may still be forming, the one before it is complete
+	count := 2
+	response, err := p.client.GetInstrumentCandles(p.instrument, GetInstrumentCandlesRequest{Granularity: &p.granularity, Count: &count})
+	if err != nil {
+		return nil, err
+	}
+	var last time.Time
+	for _, candle := range response.Candles {
+		if candle.Complete {
+			last = candle.Time
+		}
+	}
+	switch {
+	case !last.IsZero():
+	case len(response.Candles) > 0:
+		// None of the candlesticks is complete yet when OANDA's clock is behind, they are all still to be delivered
+		last = previousPeriodStart(p.aligner, response.Candles[0].Time)
+	default:
+		last = previousPeriodStart(p.aligner, p.now())
+	}
+
+	candles := make(chan Candlestick)
+	go func() {
+		defer close(candles)
+		wait := p.untilNextPoll()
+		retries := 0
+		for p.sleep(ctx, wait) {
+			includeFirst := false
+			response, err := p.client.GetInstrumentCandles(p.instrument, GetInstrumentCandlesRequest{
+				Granularity:  &p.granularity,
+				From:         &last,
+				IncludeFirst: &includeFirst,
+			})
+			if err == nil {
+				for _, candle := range response.Candles {
+					if !candle.Complete || !candle.Time.After(last) {
+						continue
+					}
+					select {
+					case candles <- candle:
+					case <-ctx.Done():
+						return
+					}
+					last = candle.Time
+				}
+			}
+			// The candlestick of the period which has just passed is still missing when OANDA's clock is behind
+			if last.Before(previousPeriodStart(p.aligner, p.now().Add(-candlePollDelay))) && retries < candlePollRetries {
+				wait, retries = candlePollRetryInterval, retries+1
+				continue
+			}
+			wait, retries = p.untilNextPoll(), 0
+		}
+	}()
+	return candles, nil
+}
+
+// untilNextPoll returns the time until the candlestick of the current period is polled for
+func (p *candlePoller) untilNextPoll() time.Duration {
+	now := p.now()
+	return p.aligner.Period(now).To.Add(candlePollDelay).Sub(now)
+}
+
+// sleepContext waits for the duration, it is false when the context is done first
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// previousPeriodStart returns the start of the period before the one covering the time
+func previousPeriodStart(aligner *CandleAligner, t time.Time) time.Time {
+	return aligner.Period(aligner.Period(t).From.Add(-time.Nanosecond)).From
+}
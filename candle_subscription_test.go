@@ -0,0 +1,145 @@
+package oanda_sdk
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock is a clock whose sleeps advance it instantly
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Sleep(ctx context.Context, d time.Duration) bool {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	c.mu.Unlock()
+	return ctx.Err() == nil
+}
+
+func TestSubscribeCandlesDeliversNewlyCompletedCandles(t *testing.T) {
+	aligner, err := NewCandleAligner(S5, CandleAlignment{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2024, 6, 4, 12, 0, 3, 0, time.UTC)
+	clock := &fakeClock{now: start}
+	var polls []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		now := clock.Now()
+		current := aligner.Period(now).From
+		previous := previousPeriodStart(aligner, now)
+		if query.Get("count") == "2" {
+			fmt.Fprintf(w, `{"candles": [{"time": "%s", "complete": true}, {"time": "%s", "complete": false}]}`,
+				previous.Format(time.RFC3339), current.Format(time.RFC3339))
+			return
+		}
+		if query.Get("includeFirst") != "false" || query.Get("from") == "" {
+			t.Error("Got ", r.URL.RawQuery)
+		}
+		polls = append(polls, now)
+		if len(polls) == 1 {
+			// OANDA's clock is behind, the candlestick which has just passed is not complete yet
+			fmt.Fprintf(w, `{"candles": [{"time": "%s", "complete": false}]}`, previous.Format(time.RFC3339))
+			return
+		}
+		// The candlestick at from is returned once more, it must not be delivered again
+		fmt.Fprintf(w, `{"candles": [{"time": "%s", "complete": true}, {"time": "%s", "complete": true}, {"time": "%s", "complete": false}]}`,
+			query.Get("from"), previous.Format(time.RFC3339), current.Format(time.RFC3339))
+	}))
+	defer server.Close()
+	poller := &candlePoller{
+		client:      NewClient(server.URL, "token", server.Client()),
+		instrument:  "EUR_USD",
+		granularity: S5,
+		aligner:     aligner,
+		now:         clock.Now,
+		sleep:       clock.Sleep,
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	candles, err := poller.subscribe(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	candle := <-candles
+	if !candle.Complete || !candle.Time.Equal(aligner.Period(start).From) {
+		t.Error("Got ", candle, " subscribed at ", start)
+	}
+	cancel()
+	for range candles {
+	}
+	// The first poll is delayed past the end of the period, the incomplete candlestick is polled for again shortly after
+	if len(polls) < 2 || !polls[0].Equal(start.Add(4*time.Second)) || !polls[1].Equal(polls[0].Add(candlePollRetryInterval)) {
+		t.Error("Got ", polls)
+	}
+}
+
+func TestSubscribeCandlesDeliversCandleIncompleteAtStart(t *testing.T) {
+	aligner, err := NewCandleAligner(S5, CandleAlignment{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Just after the end of a period, while OANDA's clock is behind
+	start := time.Date(2024, 6, 4, 12, 0, 5, 100_000_000, time.UTC)
+	clock := &fakeClock{now: start}
+	previous := previousPeriodStart(aligner, start)
+	current := aligner.Period(start).From
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("count") == "2" {
+			fmt.Fprintf(w, `{"candles": [{"time": "%s", "complete": false}, {"time": "%s", "complete": false}]}`,
+				previous.Format(time.RFC3339), current.Format(time.RFC3339))
+			return
+		}
+		if from, _ := time.Parse(time.RFC3339, query.Get("from")); !from.Before(previous) {
+			t.Error("Got ", r.URL.RawQuery)
+		}
+		fmt.Fprintf(w, `{"candles": [{"time": "%s", "complete": true}, {"time": "%s", "complete": true}, {"time": "%s", "complete": false}]}`,
+			previous.Format(time.RFC3339), current.Format(time.RFC3339), aligner.Period(clock.Now()).From.Format(time.RFC3339))
+	}))
+	defer server.Close()
+	poller := &candlePoller{
+		client:      NewClient(server.URL, "token", server.Client()),
+		instrument:  "EUR_USD",
+		granularity: S5,
+		aligner:     aligner,
+		now:         clock.Now,
+		sleep:       clock.Sleep,
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	candles, err := poller.subscribe(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var delivered []time.Time
+	for len(delivered) < 2 {
+		select {
+		case candle := <-candles:
+			delivered = append(delivered, candle.Time)
+		case <-time.After(3 * time.Second):
+			t.Fatal("Got ", delivered)
+		}
+	}
+	// The candlestick which was not complete at start is delivered once it is
+	if !delivered[0].Equal(previous) || !delivered[1].Equal(current) {
+		t.Error("Got ", delivered)
+	}
+	cancel()
+	for range candles {
+	}
+}
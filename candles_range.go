@@ -0,0 +1,109 @@
+package oanda_sdk
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"time"
+)
+
+const (
+	// maxCandlesPerRequest is the maximum number of candlesticks OANDA returns per request
+	maxCandlesPerRequest = 5000
+
+	// candleFetchConcurrency is the number of candlestick requests a range download has in flight at once. The
+	// requests are still subject to the rate limit of the Client.
+	candleFetchConcurrency = 4
+)
+
+// FetchCandlesRange downloads the mid candlesticks of an instrument between from and to, however long the range is
+// (see FetchCandles)
+func (c *Client) FetchCandlesRange(instrument string, granularity CandlestickGranularity, from, to time.Time) ([]Candlestick, error) {
+	return c.FetchCandles(instrument, GetInstrumentCandlesRequest{Granularity: &granularity, From: &from, To: &to})
+}
+
+// FetchCandles downloads the candlesticks of an instrument between the From and To of the request, which are both
+// required, however long the range is. The range is split into chunks fitting into a single request each, which are
+// fetched concurrently. The candlesticks are returned ordered by time, without the duplicates at the boundaries of
+// the chunks. The other options of the request apply to every chunk, its Count is ignored.
+func (c *Client) FetchCandles(instrument string, request GetInstrumentCandlesRequest) ([]Candlestick, error) {
+	var candles []Candlestick
+	for candle, err := range c.CandlesIter(instrument, request) {
+		if err != nil {
+			return nil, err
+		}
+		candles = append(candles, candle)
+	}
+	return candles, nil
+}
+
+// CandlesIter streams the candlesticks of an instrument between the From and To of the request like FetchCandles,
+// fetching the chunks lazily as the iteration proceeds. An error fetching a chunk is yielded once and ends the
+// iteration.
+func (c *Client) CandlesIter(instrument string, request GetInstrumentCandlesRequest) iter.Seq2[Candlestick, error] {
+	return func(yield func(Candlestick, error) bool) {
+		chunks, err := candleChunks(request)
+		if err != nil {
+			yield(Candlestick{}, err)
+			return
+		}
+		var last time.Time
+		for len(chunks) > 0 {
+			batch := chunks[:min(len(chunks), candleFetchConcurrency)]
+			chunks = chunks[len(batch):]
+			responses := make([]*GetInstrumentCandlesResponse, len(batch))
+			errs := make([]error, len(batch))
+			forEachConcurrently(len(batch), func(i int) {
+				responses[i], errs[i] = c.GetInstrumentCandles(instrument, batch[i])
+			})
+			for i, response := range responses {
+				if errs[i] != nil {
+					yield(Candlestick{}, fmt.Errorf("fetching candles from %s: %w", batch[i].From.Format(time.RFC3339), errs[i]))
+					return
+				}
+				for _, candle := range response.Candles {
+					// The candlestick at the boundary of two chunks is returned for both of them
+					if !last.IsZero() && !candle.Time.After(last) {
+						continue
+					}
+					last = candle.Time
+					if !yield(candle, nil) {
+						return
+					}
+				}
+			}
+		}
+	}
+}
+
+// candleChunks splits the time range of the request into requests of at most maxCandlesPerRequest candlesticks
+func candleChunks(request GetInstrumentCandlesRequest) ([]GetInstrumentCandlesRequest, error) {
+	if request.From == nil || request.To == nil {
+		return nil, errors.New("both from and to are required")
+	}
+	granularity := S5
+	if request.Granularity != nil {
+		granularity = *request.Granularity
+	}
+	duration := granularity.Duration()
+	if duration == 0 {
+		return nil, fmt.Errorf("unknown granularity %s", granularity)
+	}
+	// One candlestick less than the maximum leaves room for a candlestick covering the start of an unaligned chunk
+	span := (maxCandlesPerRequest - 1) * duration
+	from, to := *request.From, *request.To
+	var chunks []GetInstrumentCandlesRequest
+	for from.Before(to) {
+		end := from.Add(span)
+		if end.After(to) {
+			end = to
+		}
+		start := from
+		chunk := request
+		chunk.Count = nil
+		chunk.From, chunk.To = &start, &end
+		chunks = append(chunks, chunk)
+		from = end
+	}
+	return chunks, nil
+}
@@ -0,0 +1,58 @@
+package oanda_sdk
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFetchCandlesRangeChunksAndDedupes(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		query := r.URL.Query()
+		if r.URL.Path != "/v3/instruments/EUR_USD/candles" || query.Get("granularity") != "M1" || query.Has("count") {
+			t.Error("Got ", r.URL)
+		}
+		from, _ := time.Parse(time.RFC3339, query.Get("from"))
+		to, _ := time.Parse(time.RFC3339, query.Get("to"))
+		// The candlestick starting at the end of the range is included, overlapping with the next chunk
+		var candles []string
+		for at := from; !at.After(to); at = at.Add(time.Minute) {
+			candles = append(candles, fmt.Sprintf(`{"time": "%s", "complete": true}`, at.Format(time.RFC3339)))
+		}
+		if len(candles) > maxCandlesPerRequest {
+			t.Error("Got ", len(candles), " candles")
+		}
+		fmt.Fprintf(w, `{"instrument": "EUR_USD", "granularity": "M1", "candles": [%s]}`, strings.Join(candles, ","))
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, "token", server.Client())
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(12000 * time.Minute)
+	candles, err := client.FetchCandlesRange("EUR_USD", M1, from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if requests.Load() != 3 || len(candles) != 12001 {
+		t.Error("Got ", requests.Load(), " requests and ", len(candles), " candles")
+	}
+	for i, candle := range candles {
+		if !candle.Time.Equal(from.Add(time.Duration(i) * time.Minute)) {
+			t.Fatal("Got ", candle.Time, " at ", i)
+		}
+	}
+}
+
+func TestCandlesIterRequiresRange(t *testing.T) {
+	client := NewClient("http://localhost", "token", http.DefaultClient)
+	for _, err := range client.CandlesIter("EUR_USD", GetInstrumentCandlesRequest{}) {
+		if err == nil {
+			t.Error("Expected an error for a missing range")
+		}
+	}
+}
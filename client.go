@@ -3,10 +3,13 @@ package oanda_sdk
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/google/go-querystring/query"
 	"net/http"
+	"net/url"
+	"sync"
 	"time"
 )
 
@@ -21,6 +24,11 @@ type Client struct {
 	baseUrl     string
 	accessToken string
 	conn        *http.Client
+	limiter     *rateLimiter
+	riskMu      sync.RWMutex
+	riskRules   []RiskRule
+	mt4Mu       sync.Mutex
+	mt4Accounts map[AccountID]bool
 }
 
 func NewClient(baseUrl, accessToken string, client *http.Client) *Client {
@@ -32,9 +40,16 @@ func NewClient(baseUrl, accessToken string, client *http.Client) *Client {
 		baseUrl:     baseUrl,
 		accessToken: accessToken,
 		conn:        client,
+		limiter:     newRateLimiter(DefaultRateLimit),
 	}
 }
 
+// SetRateLimit sets the maximum number of requests per second sent by the Client. Requests over the limit are delayed
+// until they fit in. A limit of zero or less disables the limiting.
+func (c *Client) SetRateLimit(requestsPerSecond int) {
+	c.limiter.setRate(requestsPerSecond)
+}
+
 func (c *Client) setHeaders(req *http.Request) {
 	req.Header.Set("User-Agent", "Oanda SDK for GO")
 	req.Header.Set("Content-Type", "application/json")
@@ -42,6 +57,12 @@ func (c *Client) setHeaders(req *http.Request) {
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.accessToken))
 }
 
+// do sends the request once the rate limit allows it
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	c.limiter.wait()
+	return c.conn.Do(req)
+}
+
 // GetAccounts returns a list of all Accounts authorized for the provided token
 func (c *Client) GetAccounts() (*GetAccountsResponse, error) {
 	req, err := http.NewRequest(http.MethodGet, c.baseUrl+"/v3/accounts", nil)
@@ -49,7 +70,7 @@ func (c *Client) GetAccounts() (*GetAccountsResponse, error) {
 		return nil, err
 	}
 	c.setHeaders(req)
-	resp, err := c.conn.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -72,7 +93,7 @@ func (c *Client) GetAccount(accountID AccountID) (*GetAccountResponse, error) {
 		return nil, err
 	}
 	c.setHeaders(req)
-	resp, err := c.conn.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -94,7 +115,7 @@ func (c *Client) GetAccountSummary(accountID AccountID) (*GetAccountSummaryRespo
 		return nil, err
 	}
 	c.setHeaders(req)
-	resp, err := c.conn.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -126,7 +147,7 @@ func (c *Client) GetAccountInstruments(accountID AccountID, instruments []string
 		return nil, err
 	}
 	c.setHeaders(req)
-	resp, err := c.conn.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -153,7 +174,7 @@ func (c *Client) SetAccountConfiguration(accountID AccountID, requestBody SetAcc
 		return nil, err
 	}
 	c.setHeaders(req)
-	resp, err := c.conn.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -186,12 +207,12 @@ func (c *Client) GetAccountChanges(accountID AccountID, sinceTransactionID Trans
 	if err != nil {
 		return nil, err
 	}
-	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/v3/accounts/%s/changes?%s", c.baseUrl, accountID, urlQuery), nil)
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/v3/accounts/%s/changes?%s", c.baseUrl, accountID, urlQuery.Encode()), nil)
 	if err != nil {
 		return nil, err
 	}
 	c.setHeaders(req)
-	resp, err := c.conn.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -217,7 +238,7 @@ func (c *Client) GetInstrumentCandles(instrument string, request GetInstrumentCa
 		return nil, err
 	}
 	c.setHeaders(req)
-	resp, err := c.conn.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -232,7 +253,8 @@ func (c *Client) GetInstrumentCandles(instrument string, request GetInstrumentCa
 	return &instrumentCandlesResponse, nil
 }
 
-// GetInstrumentOrderBook fetches an order book for an instrument
+// GetInstrumentOrderBook fetches an order book for an instrument. The latest snapshot is fetched when the snapshot
+// time is nil, ErrBookNotFound is returned when there is no snapshot at the time.
 func (c *Client) GetInstrumentOrderBook(instrument string, snapshotTime *time.Time) (*GetInstrumentOrderBookResponse, error) {
 	urlQuery, err := query.Values(struct {
 		Time *time.Time `url:"time,omitempty"`
@@ -242,15 +264,18 @@ func (c *Client) GetInstrumentOrderBook(instrument string, snapshotTime *time.Ti
 	if err != nil {
 		return nil, err
 	}
-	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/v3/instruments/%s/orderBook?%s", c.baseUrl, instrument, urlQuery), nil)
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/v3/instruments/%s/orderBook?%s", c.baseUrl, instrument, urlQuery.Encode()), nil)
 	if err != nil {
 		return nil, err
 	}
 	c.setHeaders(req)
-	resp, err := c.conn.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrBookNotFound
+	}
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("received an HTTP %d response", resp.StatusCode)
 	}
@@ -262,7 +287,8 @@ func (c *Client) GetInstrumentOrderBook(instrument string, snapshotTime *time.Ti
 	return &instrumentOrderBookResponse, nil
 }
 
-// GetInstrumentPositionBook fetches a position book for an instrument
+// GetInstrumentPositionBook fetches a position book for an instrument. The latest snapshot is fetched when the
+// snapshot time is nil, ErrBookNotFound is returned when there is no snapshot at the time.
 func (c *Client) GetInstrumentPositionBook(instrument string, snapshotTime *time.Time) (*GetInstrumentPositionBookResponse, error) {
 	urlQuery, err := query.Values(struct {
 		Time *time.Time `url:"time,omitempty"`
@@ -272,15 +298,18 @@ func (c *Client) GetInstrumentPositionBook(instrument string, snapshotTime *time
 	if err != nil {
 		return nil, err
 	}
-	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/v3/instruments/%s/positionBook?%s", c.baseUrl, instrument, urlQuery), nil)
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/v3/instruments/%s/positionBook?%s", c.baseUrl, instrument, urlQuery.Encode()), nil)
 	if err != nil {
 		return nil, err
 	}
 	c.setHeaders(req)
-	resp, err := c.conn.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrBookNotFound
+	}
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("received an HTTP %d response", resp.StatusCode)
 	}
@@ -292,10 +321,15 @@ func (c *Client) GetInstrumentPositionBook(instrument string, snapshotTime *time
 	return &instrumentPositionBookResponse, nil
 }
 
-// CreateOrder creates an Order for an Account
+// CreateOrder creates an Order for an Account. The OrderRequest is checked by the RiskRules of the Client before it is
+// sent, see [Client.AddRiskRules].
 func (c *Client) CreateOrder(accountID AccountID, orderRequest OrderRequest) (*CreateOrderResponse, error) {
+	err := c.checkRisk(accountID, orderRequest, nil)
+	if err != nil {
+		return nil, err
+	}
 	var buf bytes.Buffer
-	err := json.NewEncoder(&buf).Encode(orderRequest)
+	err = json.NewEncoder(&buf).Encode(orderRequest)
 	if err != nil {
 		return nil, err
 	}
@@ -304,7 +338,7 @@ func (c *Client) CreateOrder(accountID AccountID, orderRequest OrderRequest) (*C
 		return nil, err
 	}
 	c.setHeaders(req)
-	resp, err := c.conn.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -338,7 +372,7 @@ func (c *Client) GetAccountOrders(accountID AccountID, request GetAccountOrdersR
 		return nil, err
 	}
 	c.setHeaders(req)
-	resp, err := c.conn.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -355,12 +389,12 @@ func (c *Client) GetAccountOrders(accountID AccountID, request GetAccountOrdersR
 
 // GetAccountPendingOrders lists all pending Orders in an Account
 func (c *Client) GetAccountPendingOrders(accountID AccountID) (*GetAccountOrdersResponse, error) {
-	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/accounts/%s/pendingOrders", c.baseUrl, accountID), nil)
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/v3/accounts/%s/pendingOrders", c.baseUrl, accountID), nil)
 	if err != nil {
 		return nil, err
 	}
 	c.setHeaders(req)
-	resp, err := c.conn.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -377,12 +411,12 @@ func (c *Client) GetAccountPendingOrders(accountID AccountID) (*GetAccountOrders
 
 // GetAccountOrder gets details for a single Order in an Account
 func (c *Client) GetAccountOrder(accountID AccountID, orderSpecifier OrderSpecifier) (*GetAccountOrderResponse, error) {
-	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/accounts/%s/orders/%s", c.baseUrl, accountID, orderSpecifier), nil)
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/v3/accounts/%s/orders/%s", c.baseUrl, accountID, url.PathEscape(string(orderSpecifier))), nil)
 	if err != nil {
 		return nil, err
 	}
 	c.setHeaders(req)
-	resp, err := c.conn.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -397,19 +431,24 @@ func (c *Client) GetAccountOrder(accountID AccountID, orderSpecifier OrderSpecif
 	return &accountOrderResponse, nil
 }
 
-// ReplaceAccountOrder replaces an Order in an Account by simultaneously cancelling it and creating a replacement Order
+// ReplaceAccountOrder replaces an Order in an Account by simultaneously cancelling it and creating a replacement Order.
+// The replacing OrderRequest is checked by the RiskRules of the Client before it is sent, see [Client.AddRiskRules].
 func (c *Client) ReplaceAccountOrder(accountID AccountID, orderSpecifier OrderSpecifier, orderRequest OrderRequest) (*ReplaceAccountOrderResponse, error) {
+	err := c.checkRisk(accountID, orderRequest, &orderSpecifier)
+	if err != nil {
+		return nil, err
+	}
 	var buf bytes.Buffer
-	err := json.NewEncoder(&buf).Encode(orderRequest)
+	err = json.NewEncoder(&buf).Encode(orderRequest)
 	if err != nil {
 		return nil, err
 	}
-	req, err := http.NewRequest(http.MethodPut, fmt.Sprintf("%s/v3/accounts/%s/orders/%s", c.baseUrl, accountID, orderSpecifier), &buf)
+	req, err := http.NewRequest(http.MethodPut, fmt.Sprintf("%s/v3/accounts/%s/orders/%s", c.baseUrl, accountID, url.PathEscape(string(orderSpecifier))), &buf)
 	if err != nil {
 		return nil, err
 	}
 	c.setHeaders(req)
-	resp, err := c.conn.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -441,12 +480,12 @@ func (c *Client) ReplaceAccountOrder(accountID AccountID, orderSpecifier OrderSp
 
 // CancelAccountOrder cancels a pending Order in an Account
 func (c *Client) CancelAccountOrder(accountID AccountID, orderSpecifier OrderSpecifier) (*CancelAccountOrderResponse, error) {
-	req, err := http.NewRequest(http.MethodPut, fmt.Sprintf("%s/v3/accounts/%s/orders/%s/cancel", c.baseUrl, accountID, orderSpecifier), nil)
+	req, err := http.NewRequest(http.MethodPut, fmt.Sprintf("%s/v3/accounts/%s/orders/%s/cancel", c.baseUrl, accountID, url.PathEscape(string(orderSpecifier))), nil)
 	if err != nil {
 		return nil, err
 	}
 	c.setHeaders(req)
-	resp, err := c.conn.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -469,18 +508,25 @@ func (c *Client) CancelAccountOrder(accountID AccountID, orderSpecifier OrderSpe
 	return nil, fmt.Errorf("received an HTTP %d response", resp.StatusCode)
 }
 
+// UpdateAccountOrderClientExtensions updates the ClientExtensions for an Order and the Trade it creates. The
+// ClientExtensions can not be added, updated or deleted if your account is associated with MT4, ErrMT4ClientExtensions
+// is returned for such Accounts without sending the request.
 func (c *Client) UpdateAccountOrderClientExtensions(accountID AccountID, orderSpecifier OrderSpecifier, updateClientExtensionsRequest UpdateClientExtensionsRequest) (*UpdateClientExtensionsResponse, error) {
+	err := c.checkClientExtensionsAllowed(accountID)
+	if err != nil {
+		return nil, err
+	}
 	var buf bytes.Buffer
-	err := json.NewEncoder(&buf).Encode(updateClientExtensionsRequest)
+	err = json.NewEncoder(&buf).Encode(updateClientExtensionsRequest)
 	if err != nil {
 		return nil, err
 	}
-	req, err := http.NewRequest(http.MethodPut, fmt.Sprintf("%s/v3/accounts/%s/orders/%s/clientExtensions", c.baseUrl, accountID, orderSpecifier), &buf)
+	req, err := http.NewRequest(http.MethodPut, fmt.Sprintf("%s/v3/accounts/%s/orders/%s/clientExtensions", c.baseUrl, accountID, url.PathEscape(string(orderSpecifier))), &buf)
 	if err != nil {
 		return nil, err
 	}
 	c.setHeaders(req)
-	resp, err := c.conn.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -514,7 +560,7 @@ func (c *Client) GetAccountTrades(accountID AccountID, request GetAccountTradesR
 		return nil, err
 	}
 	c.setHeaders(req)
-	resp, err := c.conn.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -536,7 +582,7 @@ func (c *Client) GetAccountOpenTrades(accountID AccountID) (*GetAccountTradesRes
 		return nil, err
 	}
 	c.setHeaders(req)
-	resp, err := c.conn.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -553,12 +599,12 @@ func (c *Client) GetAccountOpenTrades(accountID AccountID) (*GetAccountTradesRes
 
 // GetAccountTrade gets the details of a specific Trade in an Account
 func (c *Client) GetAccountTrade(accountID AccountID, tradeSpecifier TradeSpecifier) (*GetAccountTradeResponse, error) {
-	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/v3/accounts/%s/trades/%s", c.baseUrl, accountID, tradeSpecifier), nil)
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/v3/accounts/%s/trades/%s", c.baseUrl, accountID, url.PathEscape(string(tradeSpecifier))), nil)
 	if err != nil {
 		return nil, err
 	}
 	c.setHeaders(req)
-	resp, err := c.conn.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -573,14 +619,24 @@ func (c *Client) GetAccountTrade(accountID AccountID, tradeSpecifier TradeSpecif
 	return &accountTradeResponse, nil
 }
 
-// CloseAccountTrade closes (partially or fully) a specific open Trade in an Account
+// CloseAccountTrade fully closes a specific open Trade in an Account
 func (c *Client) CloseAccountTrade(accountID AccountID, tradeSpecifier TradeSpecifier) (*CloseAccountTradeResponse, error) {
-	req, err := http.NewRequest(http.MethodPut, fmt.Sprintf("%s/v3/accounts/%s/trades/%s/close", c.baseUrl, accountID, tradeSpecifier), nil)
+	return c.CloseAccountTradeUnits(accountID, tradeSpecifier, CloseAccountTradeRequest{})
+}
+
+// CloseAccountTradeUnits closes (partially or fully) a specific open Trade in an Account
+func (c *Client) CloseAccountTradeUnits(accountID AccountID, tradeSpecifier TradeSpecifier, request CloseAccountTradeRequest) (*CloseAccountTradeResponse, error) {
+	var buf bytes.Buffer
+	err := json.NewEncoder(&buf).Encode(request)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest(http.MethodPut, fmt.Sprintf("%s/v3/accounts/%s/trades/%s/close", c.baseUrl, accountID, url.PathEscape(string(tradeSpecifier))), &buf)
 	if err != nil {
 		return nil, err
 	}
 	c.setHeaders(req)
-	resp, err := c.conn.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -605,15 +661,28 @@ func (c *Client) CloseAccountTrade(accountID AccountID, tradeSpecifier TradeSpec
 	return nil, fmt.Errorf("received an HTTP %d response", resp.StatusCode)
 }
 
-// UpdateAccountTradeClientExtensions updates the ClientExtensions for a Trade. Do not add, update or delete the
-// ClientExtensions if your account is associated with MT4.
-func (c *Client) UpdateAccountTradeClientExtensions(accountID AccountID, tradeSpecifier TradeSpecifier) (*UpdateAccountTradeResponse, error) {
-	req, err := http.NewRequest(http.MethodPut, fmt.Sprintf("%s/v3/accounts/%s/trades/%s/clientExtensions", c.baseUrl, accountID, tradeSpecifier), nil)
+// UpdateAccountTradeClientExtensions updates the ClientExtensions for a Trade. Only the ClientExtensions of the request
+// are sent, its TradeClientExtensions apply to Orders only. The ClientExtensions can not be added, updated or deleted
+// if your account is associated with MT4, ErrMT4ClientExtensions is returned for such Accounts without sending the
+// request.
+func (c *Client) UpdateAccountTradeClientExtensions(accountID AccountID, tradeSpecifier TradeSpecifier, updateClientExtensionsRequest UpdateClientExtensionsRequest) (*UpdateAccountTradeResponse, error) {
+	err := c.checkClientExtensionsAllowed(accountID)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	err = json.NewEncoder(&buf).Encode(struct {
+		ClientExtensions ClientExtensions `json:"clientExtensions"`
+	}{updateClientExtensionsRequest.ClientExtensions})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest(http.MethodPut, fmt.Sprintf("%s/v3/accounts/%s/trades/%s/clientExtensions", c.baseUrl, accountID, url.PathEscape(string(tradeSpecifier))), &buf)
 	if err != nil {
 		return nil, err
 	}
 	c.setHeaders(req)
-	resp, err := c.conn.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -639,19 +708,19 @@ func (c *Client) UpdateAccountTradeClientExtensions(accountID AccountID, tradeSp
 }
 
 // UpdateAccountTradeOrders creates, replaces and cancels a Trade's dependent Orders (TakeProfit, StopLoss and
-// TrailingStopLoss) through the Trade itself
+// TrailingStopLoss) through the Trade itself. ErrTradeNotFound is returned when the Trade does not exist.
 func (c *Client) UpdateAccountTradeOrders(accountID AccountID, tradeSpecifier TradeSpecifier, updateAccountTradeOrdersRequest UpdateAccountTradeOrdersRequest) (*UpdateAccountTradeOrdersResponse, error) {
 	var buf bytes.Buffer
 	err := json.NewEncoder(&buf).Encode(updateAccountTradeOrdersRequest)
 	if err != nil {
 		return nil, err
 	}
-	req, err := http.NewRequest(http.MethodPut, fmt.Sprintf("%s/v3/accounts/%s/trades/%s/orders", c.baseUrl, accountID, tradeSpecifier), &buf)
+	req, err := http.NewRequest(http.MethodPut, fmt.Sprintf("%s/v3/accounts/%s/trades/%s/orders", c.baseUrl, accountID, url.PathEscape(string(tradeSpecifier))), &buf)
 	if err != nil {
 		return nil, err
 	}
 	c.setHeaders(req)
-	resp, err := c.conn.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -670,6 +739,8 @@ func (c *Client) UpdateAccountTradeOrders(accountID AccountID, tradeSpecifier Tr
 			return nil, err
 		}
 		return nil, errorResponse
+	case http.StatusNotFound:
+		return nil, ErrTradeNotFound
 	}
 	return nil, fmt.Errorf("received an HTTP %d response", resp.StatusCode)
 }
@@ -682,7 +753,7 @@ func (c *Client) GetAccountPositions(accountID AccountID) (*GetAccountPositionsR
 		return nil, err
 	}
 	c.setHeaders(req)
-	resp, err := c.conn.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -705,7 +776,7 @@ func (c *Client) GetAccountOpenPositions(accountID AccountID) (*GetAccountPositi
 		return nil, err
 	}
 	c.setHeaders(req)
-	resp, err := c.conn.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -728,7 +799,7 @@ func (c *Client) GetAccountInstrumentPosition(accountID AccountID, instrument st
 		return nil, err
 	}
 	c.setHeaders(req)
-	resp, err := c.conn.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -755,7 +826,7 @@ func (c *Client) CloseAccountInstrumentPosition(accountID AccountID, instrument
 		return nil, err
 	}
 	c.setHeaders(req)
-	resp, err := c.conn.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -786,12 +857,12 @@ func (c *Client) GetAccountTransactions(accountID AccountID, request GetAccountT
 	if err != nil {
 		return nil, err
 	}
-	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/v3/accounts/%s/transactions?%s", c.baseUrl, accountID, urlQuery), nil)
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/v3/accounts/%s/transactions?%s", c.baseUrl, accountID, urlQuery.Encode()), nil)
 	if err != nil {
 		return nil, err
 	}
 	c.setHeaders(req)
-	resp, err := c.conn.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -813,7 +884,7 @@ func (c *Client) GetAccountTransaction(accountID AccountID, transactionID Transa
 		return nil, err
 	}
 	c.setHeaders(req)
-	resp, err := c.conn.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -834,12 +905,12 @@ func (c *Client) GetAccountTransactionsByIdRange(accountID AccountID, request Ge
 	if err != nil {
 		return nil, err
 	}
-	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/v3/accounts/%s/transactions/idrange?%s", c.baseUrl, accountID, urlQuery), nil)
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/v3/accounts/%s/transactions/idrange?%s", c.baseUrl, accountID, urlQuery.Encode()), nil)
 	if err != nil {
 		return nil, err
 	}
 	c.setHeaders(req)
-	resp, err := c.conn.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -860,12 +931,12 @@ func (c *Client) GetAccountTransactionsSinceId(accountID AccountID, request GetA
 	if err != nil {
 		return nil, err
 	}
-	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/v3/accounts/%s/transactions/idrange?%s", c.baseUrl, accountID, urlQuery), nil)
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/v3/accounts/%s/transactions/idrange?%s", c.baseUrl, accountID, urlQuery.Encode()), nil)
 	if err != nil {
 		return nil, err
 	}
 	c.setHeaders(req)
-	resp, err := c.conn.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -880,24 +951,38 @@ func (c *Client) GetAccountTransactionsSinceId(accountID AccountID, request GetA
 	return &getAccountTransactionsResponse, nil
 }
 
+// GetAccountTransactionsStream streams the Transactions of an Account as they are created. The stream runs until the
+// connection is closed, use GetAccountTransactionsStreamContext to be able to stop it.
 func (c *Client) GetAccountTransactionsStream(accountID AccountID) (<-chan Transaction, error) {
-	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/v3/accounts/%s/transactions/stream", c.baseUrl, accountID), nil)
+	return c.GetAccountTransactionsStreamContext(context.Background(), accountID)
+}
+
+// GetAccountTransactionsStreamContext streams the Transactions of an Account as they are created. The stream is closed
+// when the context is done.
+func (c *Client) GetAccountTransactionsStreamContext(ctx context.Context, accountID AccountID) (<-chan Transaction, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/v3/accounts/%s/transactions/stream", c.baseUrl, accountID), nil)
 	if err != nil {
 		return nil, err
 	}
 	c.setHeaders(req)
-	resp, err := c.conn.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
 	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
 		return nil, fmt.Errorf("received an HTTP %d response", resp.StatusCode)
 	}
 	transactions := make(chan Transaction)
 	go func() {
+		defer resp.Body.Close()
+		defer close(transactions)
 		reader := bufio.NewReader(resp.Body)
 		for {
 			line, err := reader.ReadBytes('\n')
+			if err != nil {
+				return
+			}
 			type message struct {
 				Type string `json:"type"`
 			}
@@ -907,12 +992,15 @@ func (c *Client) GetAccountTransactionsStream(accountID AccountID) (<-chan Trans
 			case "HEARTBEAT":
 				continue
 			default:
-				var transaction Transaction
-				err = json.NewDecoder(bytes.NewReader(line)).Decode(&transaction)
-				if err != nil {
+				transaction, err := UnmarshalTransaction(line)
+				if err != nil || transaction == nil {
 					continue
 				}
-				transactions <- transaction
+				select {
+				case transactions <- transaction:
+				case <-ctx.Done():
+					return
+				}
 			}
 		}
 	}()
@@ -927,12 +1015,12 @@ func (c *Client) GetAccountLatestCandles(accountID AccountID, request GetAccount
 	if err != nil {
 		return nil, err
 	}
-	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/v3/accounts/%s/candles/latest?%s", c.baseUrl, accountID, urlQuery), nil)
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/v3/accounts/%s/candles/latest?%s", c.baseUrl, accountID, urlQuery.Encode()), nil)
 	if err != nil {
 		return nil, err
 	}
 	c.setHeaders(req)
-	resp, err := c.conn.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -953,12 +1041,12 @@ func (c *Client) GetAccountPricing(accountID AccountID, request GetAccountPricin
 	if err != nil {
 		return nil, err
 	}
-	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/v3/accounts/%s/pricing?%s", c.baseUrl, accountID, urlQuery), nil)
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/v3/accounts/%s/pricing?%s", c.baseUrl, accountID, urlQuery.Encode()), nil)
 	if err != nil {
 		return nil, err
 	}
 	c.setHeaders(req)
-	resp, err := c.conn.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -979,12 +1067,12 @@ func (c *Client) GetAccountInstrumentCandles(accountID AccountID, instrument str
 	if err != nil {
 		return nil, err
 	}
-	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/v3/accounts/%s/instruments/%s/candles?%s", c.baseUrl, accountID, instrument, urlQuery), nil)
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/v3/accounts/%s/instruments/%s/candles?%s", c.baseUrl, accountID, instrument, urlQuery.Encode()), nil)
 	if err != nil {
 		return nil, err
 	}
 	c.setHeaders(req)
-	resp, err := c.conn.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -999,28 +1087,42 @@ func (c *Client) GetAccountInstrumentCandles(accountID AccountID, instrument str
 	return &getAccountInstrumentCandlesResponse, nil
 }
 
+// GetAccountPricingStream streams the Prices of the requested instruments. The stream runs until the connection is
+// closed, use GetAccountPricingStreamContext to be able to stop it.
 func (c *Client) GetAccountPricingStream(accountID AccountID, request GetAccountPricingStreamRequest) (<-chan ClientPrice, error) {
+	return c.GetAccountPricingStreamContext(context.Background(), accountID, request)
+}
+
+// GetAccountPricingStreamContext streams the Prices of the requested instruments. The stream is closed when the
+// context is done.
+func (c *Client) GetAccountPricingStreamContext(ctx context.Context, accountID AccountID, request GetAccountPricingStreamRequest) (<-chan ClientPrice, error) {
 	urlQuery, err := query.Values(request)
 	if err != nil {
 		return nil, err
 	}
-	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/v3/accounts/%s/pricing/stream?%s", c.baseUrl, accountID, urlQuery), nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/v3/accounts/%s/pricing/stream?%s", c.baseUrl, accountID, urlQuery.Encode()), nil)
 	if err != nil {
 		return nil, err
 	}
 	c.setHeaders(req)
-	resp, err := c.conn.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
 	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
 		return nil, fmt.Errorf("received an HTTP %d response", resp.StatusCode)
 	}
 	prices := make(chan ClientPrice)
 	go func() {
+		defer resp.Body.Close()
+		defer close(prices)
 		reader := bufio.NewReader(resp.Body)
 		for {
 			line, err := reader.ReadBytes('\n')
+			if err != nil {
+				return
+			}
 			type message struct {
 				Type string `json:"type"`
 			}
@@ -1035,10 +1137,28 @@ func (c *Client) GetAccountPricingStream(accountID AccountID, request GetAccount
 				if err != nil {
 					continue
 				}
-				prices <- price
+				select {
+				case prices <- price:
+				case <-ctx.Done():
+					return
+				}
 			}
 		}
 	}()
 
 	return prices, nil
 }
+
+// openPricingStream opens the pricing stream of the instruments, it is closed by the returned function or once the
+// context is done
+func (c *Client) openPricingStream(ctx context.Context, accountID AccountID, instruments []string) (<-chan ClientPrice, context.CancelFunc, error) {
+	streamCtx, cancel := context.WithCancel(ctx)
+	prices, err := c.GetAccountPricingStreamContext(streamCtx, accountID, GetAccountPricingStreamRequest{
+		Instruments: instruments,
+	})
+	if err != nil {
+		cancel()
+		return nil, func() {}, err
+	}
+	return prices, cancel, nil
+}
@@ -0,0 +1,65 @@
+package oanda_sdk
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrMT4ClientExtensions is returned when updating the ClientExtensions of an Order or a Trade in an Account associated
+// with MT4, which does not allow them to be added, updated or deleted
+var ErrMT4ClientExtensions = errors.New("client extensions can not be modified in an account associated with MT4")
+
+// checkClientExtensionsAllowed fails with ErrMT4ClientExtensions for Accounts associated with MT4. Whether an Account
+// is associated with MT4 is looked up once and remembered by the Client.
+func (c *Client) checkClientExtensionsAllowed(accountID AccountID) error {
+	c.mt4Mu.Lock()
+	defer c.mt4Mu.Unlock()
+	mt4, ok := c.mt4Accounts[accountID]
+	if !ok {
+		accounts, err := c.GetAccounts()
+		if err != nil {
+			return err
+		}
+		if c.mt4Accounts == nil {
+			c.mt4Accounts = make(map[AccountID]bool)
+		}
+		for _, account := range accounts.Accounts {
+			c.mt4Accounts[account.ID] = account.Mt4AccountID != nil
+		}
+		mt4 = c.mt4Accounts[accountID]
+	}
+	if mt4 {
+		return ErrMT4ClientExtensions
+	}
+	return nil
+}
+
+// TagTrade sets the tag of the ClientExtensions of a Trade, typically to the identifier of the strategy managing it.
+// The ID and comment of the ClientExtensions are kept.
+func (c *Client) TagTrade(accountID AccountID, tradeSpecifier TradeSpecifier, tag ClientTag) (*UpdateAccountTradeResponse, error) {
+	trade, err := c.GetAccountTrade(accountID, tradeSpecifier)
+	if err != nil {
+		return nil, err
+	}
+	clientExtensions := trade.Trade.ClientExtensions
+	clientExtensions.Tag = tag
+	return c.UpdateAccountTradeClientExtensions(accountID, tradeSpecifier, UpdateClientExtensionsRequest{
+		ClientExtensions: clientExtensions,
+	})
+}
+
+// GetAccountTradesByTag lists the Trades of an Account with the given tag in their ClientExtensions. OANDA can not
+// filter Trades by tag, so all Trades matching the request are fetched page by page (see TradesIter) and filtered by
+// the Client.
+func (c *Client) GetAccountTradesByTag(accountID AccountID, tag ClientTag, request GetAccountTradesRequest) ([]Trade, error) {
+	var trades []Trade
+	for trade, err := range c.TradesIter(accountID, request, time.Time{}) {
+		if err != nil {
+			return nil, err
+		}
+		if trade.ClientExtensions.Tag == tag {
+			trades = append(trades, trade)
+		}
+	}
+	return trades, nil
+}
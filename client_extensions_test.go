@@ -0,0 +1,91 @@
+package oanda_sdk
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func newClientExtensionsServer(t *testing.T, updates *[]UpdateClientExtensionsRequest) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/v3/accounts":
+			w.Write([]byte(`{"accounts": [{"id": "001"}, {"id": "002", "mt4AccountID": 7}]}`))
+		case r.URL.Path == "/v3/accounts/001/trades/42":
+			w.Write([]byte(`{"trade": {"id": "42", "clientExtensions": {"id": "entry-42", "tag": "old"}}}`))
+		case strings.HasSuffix(r.URL.Path, "/clientExtensions"):
+			// The Trade endpoint only takes the clientExtensions
+			var body map[string]ClientExtensions
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil || len(body) != 1 {
+				t.Error("Got ", body, err)
+			}
+			*updates = append(*updates, UpdateClientExtensionsRequest{ClientExtensions: body["clientExtensions"]})
+			w.Write([]byte(`{"lastTransactionID": "60"}`))
+		case r.URL.Path == "/v3/accounts/001/trades":
+			// Trades 1 to 5 in pages of two, the odd ones tagged "trend"
+			before := 5
+			if beforeID := r.URL.Query().Get("beforeID"); beforeID != "" {
+				before, _ = strconv.Atoi(beforeID)
+			}
+			var trades []string
+			for id := before; id > 0 && id > before-2; id-- {
+				tag := "range"
+				if id%2 == 1 {
+					tag = "trend"
+				}
+				trades = append(trades, fmt.Sprintf(`{"id": "%d", "clientExtensions": {"tag": "%s"}}`, id, tag))
+			}
+			w.Write([]byte(`{"trades": [` + strings.Join(trades, ",") + `]}`))
+		default:
+			t.Error("Got ", r.URL.Path)
+		}
+	}))
+}
+
+func TestTagTradeKeepsClientID(t *testing.T) {
+	var updates []UpdateClientExtensionsRequest
+	server := newClientExtensionsServer(t, &updates)
+	defer server.Close()
+	client := NewClient(server.URL, "token", server.Client())
+	_, err := client.TagTrade("001", "42", "trend")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(updates) != 1 || updates[0].ClientExtensions.Id != "entry-42" || updates[0].ClientExtensions.Tag != "trend" {
+		t.Error("Got ", updates)
+	}
+}
+
+func TestUpdateTradeClientExtensionsRefusesMT4Accounts(t *testing.T) {
+	var updates []UpdateClientExtensionsRequest
+	server := newClientExtensionsServer(t, &updates)
+	defer server.Close()
+	client := NewClient(server.URL, "token", server.Client())
+	_, err := client.UpdateAccountTradeClientExtensions("002", "42", UpdateClientExtensionsRequest{})
+	if !errors.Is(err, ErrMT4ClientExtensions) || len(updates) != 0 {
+		t.Error("Got ", err, updates)
+	}
+}
+
+func TestGetAccountTradesByTag(t *testing.T) {
+	server := newClientExtensionsServer(t, nil)
+	defer server.Close()
+	client := NewClient(server.URL, "token", server.Client())
+	count := 2
+	trades, err := client.GetAccountTradesByTag("001", "trend", GetAccountTradesRequest{Count: &count})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []TradeID
+	for _, trade := range trades {
+		ids = append(ids, trade.Id)
+	}
+	if fmt.Sprint(ids) != "[5 3 1]" {
+		t.Error("Got ", ids)
+	}
+}
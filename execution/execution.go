@@ -0,0 +1,350 @@
+// Package execution implements client-side synthetic Orders (iceberg, TWAP and trailing entry) on top of the Orders
+// natively supported by OANDA.
+package execution
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	oanda "github.com/czechnorris/oanda-sdk"
+	"github.com/shopspring/decimal"
+	"sync"
+	"time"
+)
+
+// ErrChildCancelled is reported when a child Order is cancelled by OANDA (or by another client) before it was filled
+var ErrChildCancelled = errors.New("child order cancelled before it was filled")
+
+// Broker is the subset of the [oanda_sdk.Client] the synthetic Orders are executed through
+type Broker interface {
+	CreateOrder(accountID oanda.AccountID, orderRequest oanda.OrderRequest) (*oanda.CreateOrderResponse, error)
+	CancelAccountOrder(accountID oanda.AccountID, orderSpecifier oanda.OrderSpecifier) (*oanda.CancelAccountOrderResponse, error)
+	GetAccountPricingStreamContext(ctx context.Context, accountID oanda.AccountID, request oanda.GetAccountPricingStreamRequest) (<-chan oanda.ClientPrice, error)
+	GetAccountTransactionsStreamContext(ctx context.Context, accountID oanda.AccountID) (<-chan oanda.Transaction, error)
+}
+
+// Engine places the child Orders of synthetic Orders for a single Account and routes the resulting fills back to them.
+// Child Orders are identified by the client ID set in their ClientExtensions, so the Engine cannot be used with
+// Accounts associated with MT4.
+type Engine struct {
+	broker    Broker
+	accountID oanda.AccountID
+
+	mu       sync.Mutex
+	sequence int
+	children map[oanda.ClientID]*child
+}
+
+// NewEngine creates an Engine placing Orders into the given Account
+func NewEngine(broker Broker, accountID oanda.AccountID) *Engine {
+	return &Engine{
+		broker:    broker,
+		accountID: accountID,
+		children:  make(map[oanda.ClientID]*child),
+	}
+}
+
+// Run subscribes to the Account's Transaction stream and feeds it into HandleTransaction until the context is done or
+// the stream ends. The stream is closed once the context is done. Running the Engine is required for synthetic Orders
+// whose children rest in the order book (e.g. Iceberg), as their fills are only reported through the stream.
+func (e *Engine) Run(ctx context.Context) error {
+	transactions, err := e.broker.GetAccountTransactionsStreamContext(ctx, e.accountID)
+	if err != nil {
+		return err
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case transaction, ok := <-transactions:
+			if !ok {
+				return errors.New("transaction stream closed")
+			}
+			e.HandleTransaction(transaction)
+		}
+	}
+}
+
+// HandleTransaction applies an OrderFillTransaction or OrderCancelTransaction to the child Order it belongs to. Other
+// Transactions and Transactions of unrelated Orders are ignored.
+func (e *Engine) HandleTransaction(transaction oanda.Transaction) {
+	switch tx := transaction.(type) {
+	case oanda.OrderFillTransaction:
+		if tx.ClientOrderID == nil {
+			return
+		}
+		if c := e.lookup(*tx.ClientOrderID); c != nil {
+			c.execution.applyFill(c, tx)
+		}
+	case oanda.OrderCancelTransaction:
+		if c := e.lookup(tx.ClientOrderID); c != nil {
+			c.execution.applyCancel(c, tx)
+		}
+	}
+}
+
+func (e *Engine) lookup(clientID oanda.ClientID) *child {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.children[clientID]
+}
+
+// newExecution prepares the parent of a synthetic Order
+func (e *Engine) newExecution(ctx context.Context, instrument string, units decimal.Decimal, tag oanda.ClientTag) (*Execution, context.Context) {
+	e.mu.Lock()
+	e.sequence++
+	id := fmt.Sprintf("syn-%d-%d", time.Now().UnixNano(), e.sequence)
+	e.mu.Unlock()
+	ctx, cancel := context.WithCancel(ctx)
+	return &Execution{
+		engine:     e,
+		id:         id,
+		tag:        tag,
+		instrument: instrument,
+		units:      units,
+		filled:     decimal.Zero,
+		notional:   decimal.Zero,
+		fillIDs:    make(map[oanda.TransactionID]bool),
+		done:       make(chan struct{}),
+		cancel:     cancel,
+	}, ctx
+}
+
+// submit places a child Order. The child is registered before the Order is sent, so that fills reported through the
+// Transaction stream ahead of the HTTP response are not lost.
+func (e *Engine) submit(ex *Execution, units decimal.Decimal, build func(oanda.ClientExtensions) oanda.OrderRequest) (*child, error) {
+	ex.mu.Lock()
+	ex.sequence++
+	clientExtensions := oanda.ClientExtensions{
+		Id:  oanda.ClientID(fmt.Sprintf("%s-%d", ex.id, ex.sequence)),
+		Tag: ex.tag,
+	}
+	c := &child{execution: ex, clientID: clientExtensions.Id, units: units, filled: decimal.Zero, done: make(chan struct{})}
+	ex.children = append(ex.children, c)
+	ex.mu.Unlock()
+
+	e.mu.Lock()
+	e.children[c.clientID] = c
+	e.mu.Unlock()
+
+	response, err := e.broker.CreateOrder(e.accountID, build(clientExtensions))
+	if err != nil {
+		e.forget(c)
+		ex.finishChild(c)
+		return nil, err
+	}
+	if created, ok := response.OrderCreateTransaction.(interface{ GetId() oanda.TransactionID }); ok {
+		ex.mu.Lock()
+		c.orderID = oanda.OrderID(created.GetId())
+		ex.mu.Unlock()
+	}
+	if response.OrderFillTransaction != nil {
+		ex.applyFill(c, *response.OrderFillTransaction)
+	}
+	if response.OrderCancelTransaction != nil {
+		ex.applyCancel(c, *response.OrderCancelTransaction)
+	}
+	return c, nil
+}
+
+// withdraw cancels a child Order which is still resting in the order book
+func (e *Engine) withdraw(c *child) error {
+	ex := c.execution
+	ex.mu.Lock()
+	orderID := c.orderID
+	finished := c.finished
+	ex.mu.Unlock()
+	if finished || orderID == "" {
+		return nil
+	}
+	_, err := e.broker.CancelAccountOrder(e.accountID, oanda.OrderByID(orderID))
+	return err
+}
+
+func (e *Engine) forget(c *child) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	delete(e.children, c.clientID)
+}
+
+// child is a single Order placed on behalf of a synthetic Order
+type child struct {
+	execution *Execution
+	clientID  oanda.ClientID
+	orderID   oanda.OrderID
+	units     decimal.Decimal
+	filled    decimal.Decimal
+	cancelled bool
+	finished  bool
+	done      chan struct{}
+}
+
+// Execution is the parent of a synthetic Order. It aggregates the fills of all of its child Orders.
+type Execution struct {
+	engine     *Engine
+	id         string
+	tag        oanda.ClientTag
+	instrument string
+	units      decimal.Decimal
+	cancel     context.CancelFunc
+	done       chan struct{}
+
+	mu       sync.Mutex
+	sequence int
+	children []*child
+	filled   decimal.Decimal
+	notional decimal.Decimal
+	fillIDs  map[oanda.TransactionID]bool
+	fills    []oanda.OrderFillTransaction
+	err      error
+}
+
+// Report summarizes the progress of a synthetic Order
+type Report struct {
+	// The instrument of the synthetic Order.
+	Instrument string
+
+	// The units requested by the synthetic Order. Negative values indicate a short Order.
+	Units decimal.Decimal
+
+	// The units filled by the child Orders so far.
+	Filled decimal.Decimal
+
+	// The units still to be filled.
+	Remaining decimal.Decimal
+
+	// The volume-weighted average price of all fills. Zero while nothing has been filled.
+	AveragePrice decimal.Decimal
+
+	// The number of child Orders placed.
+	ChildOrders int
+
+	// Flag indicating that the synthetic Order has finished, either fully filled, cancelled or failed.
+	Finished bool
+
+	// Flag indicating that all the requested units have been filled.
+	Complete bool
+
+	// The error that stopped the synthetic Order, if any.
+	Err error
+
+	// The fills of the child Orders.
+	Fills []oanda.OrderFillTransaction
+}
+
+// ID returns the identifier of the synthetic Order. All its child Orders have a client ID prefixed by it.
+func (ex *Execution) ID() string {
+	return ex.id
+}
+
+// Done returns a channel that is closed when the synthetic Order finishes
+func (ex *Execution) Done() <-chan struct{} {
+	return ex.done
+}
+
+// Cancel stops the synthetic Order. Resting child Orders are cancelled, fills received so far are kept.
+func (ex *Execution) Cancel() {
+	ex.cancel()
+}
+
+// Wait blocks until the synthetic Order finishes or the context is done and returns its final Report
+func (ex *Execution) Wait(ctx context.Context) (Report, error) {
+	select {
+	case <-ex.done:
+		report := ex.Report()
+		return report, report.Err
+	case <-ctx.Done():
+		return ex.Report(), ctx.Err()
+	}
+}
+
+// Report returns the current progress of the synthetic Order
+func (ex *Execution) Report() Report {
+	ex.mu.Lock()
+	defer ex.mu.Unlock()
+	report := Report{
+		Instrument:   ex.instrument,
+		Units:        ex.units,
+		Filled:       ex.filled,
+		Remaining:    ex.units.Sub(ex.filled),
+		AveragePrice: decimal.Zero,
+		ChildOrders:  len(ex.children),
+		Complete:     ex.filled.Equal(ex.units),
+		Err:          ex.err,
+		Fills:        append([]oanda.OrderFillTransaction(nil), ex.fills...),
+	}
+	select {
+	case <-ex.done:
+		report.Finished = true
+	default:
+	}
+	if !ex.filled.IsZero() {
+		report.AveragePrice = ex.notional.Div(ex.filled.Abs())
+	}
+	return report
+}
+
+func (ex *Execution) remaining() decimal.Decimal {
+	ex.mu.Lock()
+	defer ex.mu.Unlock()
+	return ex.units.Sub(ex.filled)
+}
+
+func (ex *Execution) applyFill(c *child, fill oanda.OrderFillTransaction) {
+	ex.mu.Lock()
+	if ex.fillIDs[fill.Id] {
+		ex.mu.Unlock()
+		return
+	}
+	ex.fillIDs[fill.Id] = true
+	ex.fills = append(ex.fills, fill)
+	ex.filled = ex.filled.Add(fill.Units)
+	ex.notional = ex.notional.Add(fill.Units.Abs().Mul(fill.FullVWAP))
+	c.filled = c.filled.Add(fill.Units)
+	complete := c.filled.Abs().GreaterThanOrEqual(c.units.Abs())
+	ex.mu.Unlock()
+	if complete {
+		ex.finishChild(c)
+	}
+}
+
+func (ex *Execution) applyCancel(c *child, _ oanda.OrderCancelTransaction) {
+	ex.mu.Lock()
+	c.cancelled = true
+	ex.mu.Unlock()
+	ex.finishChild(c)
+}
+
+func (ex *Execution) finishChild(c *child) {
+	ex.mu.Lock()
+	if c.finished {
+		ex.mu.Unlock()
+		return
+	}
+	c.finished = true
+	close(c.done)
+	ex.mu.Unlock()
+	ex.engine.forget(c)
+}
+
+// finish marks the synthetic Order as finished
+func (ex *Execution) finish(err error) {
+	ex.mu.Lock()
+	if errors.Is(err, context.Canceled) {
+		err = nil
+	}
+	ex.err = err
+	ex.mu.Unlock()
+	ex.cancel()
+	close(ex.done)
+}
+
+// sliceUnits returns the units of the next child Order: the given size limited by the remaining units, truncated to
+// the instrument's trade units precision and signed in the direction of the parent Order
+func sliceUnits(remaining, size decimal.Decimal, precision int32) decimal.Decimal {
+	units := decimal.Min(size.Abs(), remaining.Abs()).Truncate(precision)
+	if remaining.IsNegative() {
+		return units.Neg()
+	}
+	return units
+}
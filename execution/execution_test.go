@@ -0,0 +1,299 @@
+package execution
+
+import (
+	"context"
+	"fmt"
+	oanda "github.com/czechnorris/oanda-sdk"
+	"github.com/shopspring/decimal"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeBroker struct {
+	mu       sync.Mutex
+	orders   []oanda.OrderRequest
+	fill     bool
+	price    decimal.Decimal
+	cancels  []oanda.OrderSpecifier
+	sequence int
+	prices   chan oanda.ClientPrice
+	stream   context.Context
+}
+
+func (b *fakeBroker) CreateOrder(_ oanda.AccountID, orderRequest oanda.OrderRequest) (*oanda.CreateOrderResponse, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.orders = append(b.orders, orderRequest)
+	b.sequence++
+	var units decimal.Decimal
+	var clientID oanda.ClientID
+	switch request := orderRequest.(type) {
+	case oanda.MarketOrderRequest:
+		units, clientID = request.Units, request.ClientExtensions.Id
+	case oanda.LimitOrderRequest:
+		units, clientID = request.Units, request.ClientExtensions.Id
+	}
+	response := &oanda.CreateOrderResponse{
+		OrderCreateTransaction: oanda.MarketOrderTransaction{
+			TransactionBase: oanda.TransactionBase{Id: oanda.TransactionID(fmt.Sprint(b.sequence * 10))},
+		},
+	}
+	if b.fill {
+		response.OrderFillTransaction = &oanda.OrderFillTransaction{
+			TransactionBase: oanda.TransactionBase{Id: oanda.TransactionID(fmt.Sprint(b.sequence*10 + 1))},
+			ClientOrderID:   &clientID,
+			Units:           units,
+			FullVWAP:        b.price.Add(decimal.NewFromInt(int64(b.sequence))),
+		}
+	}
+	return response, nil
+}
+
+func (b *fakeBroker) CancelAccountOrder(_ oanda.AccountID, orderSpecifier oanda.OrderSpecifier) (*oanda.CancelAccountOrderResponse, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.cancels = append(b.cancels, orderSpecifier)
+	return &oanda.CancelAccountOrderResponse{}, nil
+}
+
+func (b *fakeBroker) GetAccountPricingStreamContext(ctx context.Context, _ oanda.AccountID, _ oanda.GetAccountPricingStreamRequest) (<-chan oanda.ClientPrice, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.stream = ctx
+	if b.prices == nil {
+		b.prices = make(chan oanda.ClientPrice)
+	}
+	return b.prices, nil
+}
+
+func (b *fakeBroker) GetAccountTransactionsStreamContext(context.Context, oanda.AccountID) (<-chan oanda.Transaction, error) {
+	return make(chan oanda.Transaction), nil
+}
+
+func (b *fakeBroker) lastOrder() oanda.OrderRequest {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.orders[len(b.orders)-1]
+}
+
+func TestTWAPSplitsUnitsIntoSlices(t *testing.T) {
+	broker := &fakeBroker{fill: true, price: decimal.NewFromInt(1)}
+	engine := NewEngine(broker, "001")
+	ex, err := engine.TWAP(context.Background(), TWAPOrder{
+		Instrument: "EUR_USD",
+		Units:      decimal.NewFromInt(1000),
+		Slices:     3,
+		Duration:   30 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, err := ex.Wait(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.Complete || report.ChildOrders != 3 {
+		t.Error("Got ", report)
+	}
+	units := make([]string, 0, len(broker.orders))
+	for _, order := range broker.orders {
+		units = append(units, order.(oanda.MarketOrderRequest).Units.String())
+	}
+	if fmt.Sprint(units) != "[333 333 334]" {
+		t.Error("Got ", units)
+	}
+	// (333*2 + 333*3 + 334*4) / 1000
+	if !report.AveragePrice.Equal(decimal.RequireFromString("3.001")) {
+		t.Error("Got ", report.AveragePrice)
+	}
+}
+
+func TestTWAPTruncatesLastSlice(t *testing.T) {
+	broker := &fakeBroker{fill: true, price: decimal.NewFromInt(1)}
+	engine := NewEngine(broker, "001")
+	ex, err := engine.TWAP(context.Background(), TWAPOrder{
+		Instrument: "EUR_USD",
+		Units:      decimal.RequireFromString("-1000.5"),
+		Slices:     3,
+		Duration:   30 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, err := ex.Wait(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	units := make([]string, 0, len(broker.orders))
+	for _, order := range broker.orders {
+		units = append(units, order.(oanda.MarketOrderRequest).Units.String())
+	}
+	if fmt.Sprint(units) != "[-333 -333 -334]" {
+		t.Error("Got ", units)
+	}
+	if report.Complete || !report.Remaining.Equal(decimal.RequireFromString("-0.5")) {
+		t.Error("Got ", report)
+	}
+}
+
+func TestIcebergPlacesNextSliceAfterFill(t *testing.T) {
+	broker := &fakeBroker{}
+	engine := NewEngine(broker, "001")
+	ex, err := engine.Iceberg(context.Background(), IcebergOrder{
+		Instrument:   "EUR_USD",
+		Units:        decimal.NewFromInt(-250),
+		VisibleUnits: decimal.NewFromInt(100),
+		Price:        decimal.RequireFromString("1.1"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, expected := range []int64{-100, -100, -50} {
+		var order oanda.LimitOrderRequest
+		for deadline := time.Now().Add(time.Second); ; {
+			broker.mu.Lock()
+			placed := len(broker.orders)
+			broker.mu.Unlock()
+			if placed == i+1 {
+				order = broker.lastOrder().(oanda.LimitOrderRequest)
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("child order %d was not placed", i+1)
+			}
+			time.Sleep(time.Millisecond)
+		}
+		if !order.Units.Equal(decimal.NewFromInt(expected)) {
+			t.Error("Got ", order.Units)
+		}
+		clientID := order.ClientExtensions.Id
+		engine.HandleTransaction(oanda.OrderFillTransaction{
+			TransactionBase: oanda.TransactionBase{Id: oanda.TransactionID(fmt.Sprint(100 + i))},
+			ClientOrderID:   &clientID,
+			Units:           order.Units,
+			FullVWAP:        order.Price,
+		})
+	}
+	report, err := ex.Wait(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.Complete || !report.AveragePrice.Equal(decimal.RequireFromString("1.1")) {
+		t.Error("Got ", report)
+	}
+}
+
+func TestIcebergCancelWithdrawsRestingChild(t *testing.T) {
+	broker := &fakeBroker{}
+	engine := NewEngine(broker, "001")
+	ex, err := engine.Iceberg(context.Background(), IcebergOrder{
+		Instrument:   "EUR_USD",
+		Units:        decimal.NewFromInt(300),
+		VisibleUnits: decimal.NewFromInt(100),
+		Price:        decimal.RequireFromString("1.1"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for deadline := time.Now().Add(time.Second); ; {
+		if report := ex.Report(); report.ChildOrders == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("child order was not placed")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	ex.Cancel()
+	report, err := ex.Wait(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Complete || len(broker.cancels) != 1 || broker.cancels[0] != "10" {
+		t.Error("Got ", report, broker.cancels)
+	}
+}
+
+func TestTrailingEntryEntersAfterReversal(t *testing.T) {
+	broker := &fakeBroker{fill: true, price: decimal.RequireFromString("1.1"), prices: make(chan oanda.ClientPrice)}
+	engine := NewEngine(broker, "001")
+	ex, err := engine.TrailingEntry(context.Background(), TrailingEntryOrder{
+		Instrument: "EUR_USD",
+		Units:      decimal.NewFromInt(1000),
+		Distance:   decimal.RequireFromString("0.0005"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ask := func(price string, tradeable bool) oanda.ClientPrice {
+		return oanda.ClientPrice{
+			Instrument: "EUR_USD",
+			Tradeable:  tradeable,
+			Asks:       []oanda.PriceBucket{{Price: decimal.RequireFromString(price)}},
+		}
+	}
+	for _, price := range []oanda.ClientPrice{
+		ask("1.1010", true),
+		ask("1.1005", true),
+		ask("1.1000", false),
+		ask("1.1008", true),
+		ask("1.1010", true),
+	} {
+		broker.prices <- price
+	}
+	report, err := ex.Wait(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.Complete || report.ChildOrders != 1 {
+		t.Error("Got ", report)
+	}
+	if order, ok := broker.lastOrder().(oanda.MarketOrderRequest); !ok || !order.Units.Equal(decimal.NewFromInt(1000)) {
+		t.Error("Got ", broker.lastOrder())
+	}
+	broker.mu.Lock()
+	defer broker.mu.Unlock()
+	if broker.stream.Err() == nil {
+		t.Error("pricing stream was not closed")
+	}
+}
+
+func TestTrailingEntryCancelledEntry(t *testing.T) {
+	broker := &fakeBroker{prices: make(chan oanda.ClientPrice)}
+	engine := NewEngine(broker, "001")
+	ex, err := engine.TrailingEntry(context.Background(), TrailingEntryOrder{
+		Instrument: "EUR_USD",
+		Units:      decimal.NewFromInt(-1000),
+		Distance:   decimal.RequireFromString("0.0005"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, price := range []string{"1.1000", "1.0990"} {
+		broker.prices <- oanda.ClientPrice{
+			Instrument: "EUR_USD",
+			Tradeable:  true,
+			Bids:       []oanda.PriceBucket{{Price: decimal.RequireFromString(price)}},
+		}
+	}
+	// The entry Order is not filled by the response, it is cancelled through the Transaction stream
+	var clientID oanda.ClientID
+	for clientID == "" {
+		broker.mu.Lock()
+		if len(broker.orders) > 0 {
+			clientID = broker.orders[0].(oanda.MarketOrderRequest).ClientExtensions.Id
+		}
+		broker.mu.Unlock()
+	}
+	select {
+	case <-ex.Done():
+		t.Fatal("finished before the entry Order was filled or cancelled")
+	case <-time.After(10 * time.Millisecond):
+	}
+	engine.HandleTransaction(oanda.OrderCancelTransaction{ClientOrderID: clientID})
+	report, err := ex.Wait(context.Background())
+	if err != ErrChildCancelled || report.Complete {
+		t.Error("Got ", report, err)
+	}
+}
@@ -0,0 +1,89 @@
+package execution
+
+import (
+	"context"
+	"errors"
+	oanda "github.com/czechnorris/oanda-sdk"
+	"github.com/shopspring/decimal"
+)
+
+// IcebergOrder specifies an Order which only ever shows a limited part of its size in the order book. The Units are
+// split into LimitOrders of at most VisibleUnits, the next one being placed once the previous one is filled.
+type IcebergOrder struct {
+	// The instrument to trade.
+	Instrument string
+
+	// The total quantity to fill. A positive number of units results in long child Orders, and a negative number of
+	// units results in short child Orders.
+	Units decimal.Decimal
+
+	// The maximum number of units shown in the order book at any time.
+	VisibleUnits decimal.Decimal
+
+	// The price threshold of the child LimitOrders.
+	Price decimal.Decimal
+
+	// The instrument's TradeUnitsPrecision. Child Order units are truncated to this precision.
+	UnitsPrecision int32
+
+	// The tag added to the ClientExtensions of every child Order.
+	Tag oanda.ClientTag
+}
+
+// Iceberg starts an iceberg Order. The Engine has to be running (see [Engine.Run]) for the fills of the resting child
+// Orders to be observed. Cancelling the Execution cancels the currently resting child Order.
+func (e *Engine) Iceberg(ctx context.Context, order IcebergOrder) (*Execution, error) {
+	if order.Units.IsZero() {
+		return nil, errors.New("iceberg order needs non-zero units")
+	}
+	if order.VisibleUnits.Truncate(order.UnitsPrecision).Sign() <= 0 {
+		return nil, errors.New("iceberg order needs positive visible units")
+	}
+	ex, ctx := e.newExecution(ctx, order.Instrument, order.Units, order.Tag)
+	go func() {
+		ex.finish(e.runIceberg(ctx, ex, order))
+	}()
+	return ex, nil
+}
+
+func (e *Engine) runIceberg(ctx context.Context, ex *Execution, order IcebergOrder) error {
+	for {
+		remaining := ex.remaining()
+		if remaining.IsZero() {
+			return nil
+		}
+		units := sliceUnits(remaining, order.VisibleUnits, order.UnitsPrecision)
+		if units.IsZero() {
+			return nil
+		}
+		c, err := e.submit(ex, units, func(clientExtensions oanda.ClientExtensions) oanda.OrderRequest {
+			orderType := oanda.Limit
+			timeInForce := oanda.GTC
+			return oanda.LimitOrderRequest{
+				Type:             &orderType,
+				Instrument:       order.Instrument,
+				Units:            units,
+				Price:            order.Price,
+				TimeInForce:      &timeInForce,
+				ClientExtensions: &clientExtensions,
+			}
+		})
+		if err != nil {
+			return err
+		}
+		select {
+		case <-c.done:
+		case <-ctx.Done():
+			if err := e.withdraw(c); err != nil {
+				return err
+			}
+			return ctx.Err()
+		}
+		ex.mu.Lock()
+		cancelled := c.cancelled && c.filled.Abs().LessThan(c.units.Abs())
+		ex.mu.Unlock()
+		if cancelled {
+			return ErrChildCancelled
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package execution
+
+import (
+	"context"
+	"errors"
+	oanda "github.com/czechnorris/oanda-sdk"
+	"github.com/shopspring/decimal"
+)
+
+// TrailingEntryOrder specifies an entry Order which follows the market while it moves in the Order's favour and enters
+// with a MarketOrder once the market reverts by Distance. A long entry trails the lowest ask price seen, a short entry
+// the highest bid price seen.
+type TrailingEntryOrder struct {
+	// The instrument to trade.
+	Instrument string
+
+	// The quantity to fill. A positive number of units results in a long entry, and a negative number of units results
+	// in a short entry.
+	Units decimal.Decimal
+
+	// The distance (in price units) the market has to revert from its best price for the entry to be triggered.
+	Distance decimal.Decimal
+
+	// The worst price that the entry MarketOrder may be filled at.
+	PriceBound *decimal.Decimal
+
+	// The tag added to the ClientExtensions of the entry Order.
+	Tag oanda.ClientTag
+}
+
+// TrailingEntry starts a trailing entry Order driven by the Account's pricing stream. Only tradeable prices are
+// considered. The Execution finishes once the entry Order is filled, or with ErrChildCancelled when it is cancelled
+// before.
+func (e *Engine) TrailingEntry(ctx context.Context, order TrailingEntryOrder) (*Execution, error) {
+	if order.Units.IsZero() {
+		return nil, errors.New("trailing entry order needs non-zero units")
+	}
+	if order.Distance.Sign() <= 0 {
+		return nil, errors.New("trailing entry order needs a positive distance")
+	}
+	ex, ctx := e.newExecution(ctx, order.Instrument, order.Units, order.Tag)
+	// The stream is closed once the Execution finishes
+	prices, err := e.broker.GetAccountPricingStreamContext(ctx, e.accountID, oanda.GetAccountPricingStreamRequest{
+		Instruments: []string{order.Instrument},
+	})
+	if err != nil {
+		ex.cancel()
+		return nil, err
+	}
+	go func() {
+		ex.finish(e.runTrailingEntry(ctx, ex, order, prices))
+	}()
+	return ex, nil
+}
+
+func (e *Engine) runTrailingEntry(ctx context.Context, ex *Execution, order TrailingEntryOrder, prices <-chan oanda.ClientPrice) error {
+	long := order.Units.IsPositive()
+	var extreme *decimal.Decimal
+	for {
+		var price oanda.ClientPrice
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case p, ok := <-prices:
+			if !ok {
+				return errors.New("pricing stream closed")
+			}
+			price = p
+		}
+		if price.Instrument != order.Instrument || !price.Tradeable {
+			continue
+		}
+		current, ok := price.BestBid()
+		if long {
+			current, ok = price.BestAsk()
+		}
+		if !ok {
+			continue
+		}
+		if extreme == nil || (long && current.LessThan(*extreme)) || (!long && current.GreaterThan(*extreme)) {
+			extreme = &current
+			continue
+		}
+		if current.Sub(*extreme).Abs().LessThan(order.Distance) {
+			continue
+		}
+		c, err := e.submit(ex, order.Units, func(clientExtensions oanda.ClientExtensions) oanda.OrderRequest {
+			orderType := oanda.Market
+			return oanda.MarketOrderRequest{
+				Type:             &orderType,
+				Instrument:       order.Instrument,
+				Units:            order.Units,
+				PriceBound:       order.PriceBound,
+				ClientExtensions: &clientExtensions,
+			}
+		})
+		if err != nil {
+			return err
+		}
+		// The fill may be reported through the Transaction stream after the Order was created
+		select {
+		case <-c.done:
+		case <-ctx.Done():
+			if err := e.withdraw(c); err != nil {
+				return err
+			}
+			return ctx.Err()
+		}
+		ex.mu.Lock()
+		cancelled := c.cancelled && c.filled.Abs().LessThan(c.units.Abs())
+		ex.mu.Unlock()
+		if cancelled {
+			return ErrChildCancelled
+		}
+		return nil
+	}
+}
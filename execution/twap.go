@@ -0,0 +1,93 @@
+package execution
+
+import (
+	"context"
+	"errors"
+	oanda "github.com/czechnorris/oanda-sdk"
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+// TWAPOrder specifies a time-weighted average price Order. The Units are split into equally sized MarketOrders sent
+// at regular intervals over the Duration.
+type TWAPOrder struct {
+	// The instrument to trade.
+	Instrument string
+
+	// The total quantity to fill. A positive number of units results in long child Orders, and a negative number of
+	// units results in short child Orders.
+	Units decimal.Decimal
+
+	// The number of child Orders to split the Units into.
+	Slices int
+
+	// The time over which the child Orders are spread. The first child Order is sent immediately, the last one after
+	// (Slices-1)/Slices of the Duration.
+	Duration time.Duration
+
+	// The instrument's TradeUnitsPrecision. Child Order units are truncated to this precision.
+	UnitsPrecision int32
+
+	// The worst price that the child MarketOrders may be filled at.
+	PriceBound *decimal.Decimal
+
+	// The tag added to the ClientExtensions of every child Order.
+	Tag oanda.ClientTag
+}
+
+// TWAP starts a time-weighted average price Order. Units of child Orders that could not be filled are carried over
+// into the following slices.
+func (e *Engine) TWAP(ctx context.Context, order TWAPOrder) (*Execution, error) {
+	if order.Slices <= 0 {
+		return nil, errors.New("TWAP order needs at least one slice")
+	}
+	if order.Units.IsZero() {
+		return nil, errors.New("TWAP order needs non-zero units")
+	}
+	ex, ctx := e.newExecution(ctx, order.Instrument, order.Units, order.Tag)
+	go func() {
+		ex.finish(e.runTWAP(ctx, ex, order))
+	}()
+	return ex, nil
+}
+
+func (e *Engine) runTWAP(ctx context.Context, ex *Execution, order TWAPOrder) error {
+	interval := order.Duration / time.Duration(order.Slices)
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for slice := 0; slice < order.Slices; slice++ {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+		timer.Reset(interval)
+		remaining := ex.remaining()
+		if remaining.IsZero() {
+			return nil
+		}
+		// The last slice takes all the remaining units, truncated like the others
+		size := remaining
+		if slicesLeft := order.Slices - slice; slicesLeft > 1 {
+			size = remaining.Div(decimal.NewFromInt(int64(slicesLeft)))
+		}
+		units := sliceUnits(remaining, size, order.UnitsPrecision)
+		if units.IsZero() {
+			continue
+		}
+		_, err := e.submit(ex, units, func(clientExtensions oanda.ClientExtensions) oanda.OrderRequest {
+			orderType := oanda.Market
+			return oanda.MarketOrderRequest{
+				Type:             &orderType,
+				Instrument:       order.Instrument,
+				Units:            units,
+				PriceBound:       order.PriceBound,
+				ClientExtensions: &clientExtensions,
+			}
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
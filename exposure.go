@@ -0,0 +1,184 @@
+package oanda_sdk
+
+import (
+	"github.com/shopspring/decimal"
+	"sort"
+	"strings"
+)
+
+// CurrencyExposure is the exposure of an Account to a single currency, netted across all instruments the currency is
+// traded in. Holding units of an instrument is exposure to its base currency, offset by an opposite exposure to its
+// quote currency worth the units at the current price.
+type CurrencyExposure struct {
+	// The currency.
+	Currency Currency
+
+	// The sum of the long exposures to the currency, in the currency.
+	Long decimal.Decimal
+
+	// The sum of the short exposures to the currency, in the currency. Always zero or negative.
+	Short decimal.Decimal
+
+	// The net exposure to the currency, in the currency.
+	Net decimal.Decimal
+
+	// The exposure to the currency with the long and short sides of each Position aggregated according to the
+	// PositionAggregationMode, summed across the Positions, in the currency. Unlike Net, the exposures of different
+	// instruments are not netted against each other. Always zero or positive.
+	Aggregated decimal.Decimal
+
+	// The net exposure converted into the home currency. Zero if no home conversion is known for the currency.
+	HomeNet decimal.Decimal
+
+	// The aggregated exposure converted into the home currency. Zero if no home conversion is known for the currency.
+	HomeAggregated decimal.Decimal
+}
+
+// ExposureReport is the exposure of an Account to each of the currencies it holds positions in
+type ExposureReport struct {
+	// The mode the long and short exposures were aggregated with.
+	Mode PositionAggregationMode
+
+	// The exposure per currency, ordered by currency.
+	Currencies []CurrencyExposure
+
+	// The total unrealized profit/loss of the Positions, in the home currency.
+	UnrealizedPL decimal.Decimal
+
+	// The currencies which could not be converted into the home currency because no home conversion was provided.
+	Unconverted []Currency
+}
+
+// Currency returns the exposure to a single currency. The second return value is false when the Account is not
+// exposed to the currency.
+func (er ExposureReport) Currency(currency Currency) (CurrencyExposure, bool) {
+	for _, exposure := range er.Currencies {
+		if exposure.Currency == currency {
+			return exposure, true
+		}
+	}
+	return CurrencyExposure{}, false
+}
+
+// CalculateExposure nets the Positions into an exposure per currency. The quote currency exposure is valued at the mid
+// price from the prices, falling back to the average price of the PositionSide for instruments without a price. The
+// exposure is converted into the home currency using the PositionValue factor of the home conversions. The aggregated
+// exposure applies the mode to the long and short sides of each Position before summing the Positions per currency,
+// the way OANDA aggregates the Position value.
+//
+// The states are optional. When provided, the unrealized profit/loss of the Positions is taken from them, as they
+// are typically more recent than the Positions (see [Client.GetAccountChanges]).
+func CalculateExposure(positions []Position, states []CalculatedPositionState, prices []ClientPrice, homeConversions []HomeConversions, mode PositionAggregationMode) *ExposureReport {
+	report := &ExposureReport{Mode: mode}
+	unrealizedPL := make(map[string]decimal.Decimal, len(positions))
+	for _, position := range positions {
+		unrealizedPL[position.Instrument] = position.UnrealizedPL
+	}
+	for _, state := range states {
+		if _, ok := unrealizedPL[state.Instrument]; ok {
+			unrealizedPL[state.Instrument] = state.NetUnrealizedPL
+		}
+	}
+	for _, pl := range unrealizedPL {
+		report.UnrealizedPL = report.UnrealizedPL.Add(pl)
+	}
+
+	midPrices := make(map[string]decimal.Decimal, len(prices))
+	for _, price := range prices {
+		bid, hasBid := price.BestBid()
+		ask, hasAsk := price.BestAsk()
+		if hasBid && hasAsk {
+			midPrices[price.Instrument] = bid.Add(ask).Div(decimal.NewFromInt(2))
+		}
+	}
+	exposures := make(map[Currency]*CurrencyExposure)
+	// add adds the exposure of the long and short side of a Position to the currency
+	add := func(currency Currency, long, short decimal.Decimal) {
+		exposure, ok := exposures[currency]
+		if !ok {
+			exposure = &CurrencyExposure{Currency: currency}
+			exposures[currency] = exposure
+		}
+		for _, amount := range []decimal.Decimal{long, short} {
+			if amount.IsPositive() {
+				exposure.Long = exposure.Long.Add(amount)
+			} else {
+				exposure.Short = exposure.Short.Add(amount)
+			}
+		}
+		switch mode {
+		case AbsoluteSum:
+			exposure.Aggregated = exposure.Aggregated.Add(long.Abs()).Add(short.Abs())
+		case MaximalSide:
+			exposure.Aggregated = exposure.Aggregated.Add(decimal.Max(long.Abs(), short.Abs()))
+		default:
+			exposure.Aggregated = exposure.Aggregated.Add(long.Add(short).Abs())
+		}
+	}
+	for _, position := range positions {
+		base, quote, ok := strings.Cut(position.Instrument, "_")
+		if !ok || (position.Long.Units.IsZero() && position.Short.Units.IsZero()) {
+			continue
+		}
+		var quoteAmounts [2]decimal.Decimal
+		for i, side := range []PositionSide{position.Long, position.Short} {
+			price, ok := midPrices[position.Instrument]
+			if !ok {
+				price = side.AveragePrice
+			}
+			quoteAmounts[i] = side.Units.Mul(price).Neg()
+		}
+		add(Currency(base), position.Long.Units, position.Short.Units)
+		add(Currency(quote), quoteAmounts[0], quoteAmounts[1])
+	}
+
+	factors := make(map[Currency]decimal.Decimal, len(homeConversions))
+	for _, conversion := range homeConversions {
+		factors[conversion.Currency] = conversion.PositionValue
+	}
+	for _, exposure := range exposures {
+		exposure.Net = exposure.Long.Add(exposure.Short)
+		factor, ok := factors[exposure.Currency]
+		if !ok {
+			report.Unconverted = append(report.Unconverted, exposure.Currency)
+			continue
+		}
+		exposure.HomeNet = exposure.Net.Mul(factor)
+		exposure.HomeAggregated = exposure.Aggregated.Mul(factor)
+	}
+	for _, exposure := range exposures {
+		report.Currencies = append(report.Currencies, *exposure)
+	}
+	sort.Slice(report.Currencies, func(i, j int) bool {
+		return report.Currencies[i].Currency < report.Currencies[j].Currency
+	})
+	sort.Slice(report.Unconverted, func(i, j int) bool {
+		return report.Unconverted[i] < report.Unconverted[j]
+	})
+	return report
+}
+
+// GetAccountExposure calculates the exposure per currency of the open Positions in an Account, valued and converted
+// with the current prices (see CalculateExposure)
+func (c *Client) GetAccountExposure(accountID AccountID, mode PositionAggregationMode) (*ExposureReport, error) {
+	positions, err := c.GetAccountOpenPositions(accountID)
+	if err != nil {
+		return nil, err
+	}
+	if len(positions.Positions) == 0 {
+		return &ExposureReport{Mode: mode}, nil
+	}
+	instruments := make([]string, 0, len(positions.Positions))
+	for _, position := range positions.Positions {
+		instruments = append(instruments, position.Instrument)
+	}
+	includeHomeConversion := true
+	pricing, err := c.GetAccountPricing(accountID, GetAccountPricingRequest{
+		Instruments:           instruments,
+		IncludeHomeConversion: &includeHomeConversion,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return CalculateExposure(positions.Positions, nil, pricing.Prices, pricing.HomeConversions, mode), nil
+}
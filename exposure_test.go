@@ -0,0 +1,51 @@
+package oanda_sdk
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestCalculateExposureNetsCurrencies(t *testing.T) {
+	d := decimal.RequireFromString
+	positions := []Position{
+		{Instrument: "EUR_USD", UnrealizedPL: d("3"), Long: PositionSide{Units: d("1000"), AveragePrice: d("1.09")}, Short: PositionSide{Units: d("-400"), AveragePrice: d("1.12")}},
+		{Instrument: "EUR_GBP", UnrealizedPL: d("-1"), Short: PositionSide{Units: d("-500"), AveragePrice: d("0.85")}},
+	}
+	prices := []ClientPrice{{
+		Instrument: "EUR_USD",
+		Bids:       []PriceBucket{{Price: d("1.0999")}},
+		Asks:       []PriceBucket{{Price: d("1.1001")}},
+	}}
+	conversions := []HomeConversions{
+		{Currency: "EUR", PositionValue: d("1.1")},
+		{Currency: "USD", PositionValue: d("1")},
+	}
+	states := []CalculatedPositionState{{Instrument: "EUR_USD", NetUnrealizedPL: d("5")}}
+
+	report := CalculateExposure(positions, states, prices, conversions, NetSum)
+	eur, _ := report.Currency("EUR")
+	usd, _ := report.Currency("USD")
+	gbp, _ := report.Currency("GBP")
+	// The hedged EUR_USD position is netted to 600 EUR, the EUR_GBP position is not netted against it
+	if !eur.Net.Equal(d("100")) || !eur.HomeNet.Equal(d("110")) || !eur.Aggregated.Equal(d("1100")) {
+		t.Error("Got ", eur)
+	}
+	if !usd.Net.Equal(d("-660")) || !usd.Long.Equal(d("440")) || !usd.HomeAggregated.Equal(d("660")) {
+		t.Error("Got ", usd)
+	}
+	// The EUR_GBP position has no price, it is valued at its average price
+	if !gbp.Net.Equal(d("425")) || !gbp.HomeNet.IsZero() {
+		t.Error("Got ", gbp)
+	}
+	if len(report.Unconverted) != 1 || report.Unconverted[0] != "GBP" || !report.UnrealizedPL.Equal(d("4")) {
+		t.Error("Got ", report)
+	}
+
+	for mode, expected := range map[PositionAggregationMode]string{AbsoluteSum: "1900", MaximalSide: "1500"} {
+		eur, _ := CalculateExposure(positions, nil, prices, conversions, mode).Currency("EUR")
+		if !eur.Aggregated.Equal(d(expected)) {
+			t.Error("Got ", mode, eur)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package oanda_sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrAccountNotFlat is returned by FlattenAccount when the Account still has open Trades or pending Orders after all
+// attempts to flatten it
+var ErrAccountNotFlat = errors.New("account still has open trades or pending orders")
+
+const (
+	// flattenAttempts is the number of rounds of cancelling and closing FlattenAccount makes before giving up
+	flattenAttempts = 5
+
+	// flattenRetryInterval is the time FlattenAccount waits for the Account to settle before verifying it again
+	flattenRetryInterval = time.Second
+)
+
+// PositionCloseResult is the outcome of closing a single Position
+type PositionCloseResult struct {
+	// The instrument of the Position.
+	Instrument string
+
+	// The response to the closeout, only set if the Position was closed.
+	Response *CloseAccountInstrumentPositionResponse
+
+	// The reason the closeout failed.
+	Err error
+}
+
+// FlattenReport describes what FlattenAccount has done to the Account
+type FlattenReport struct {
+	// The number of rounds of cancelling and closing that were made.
+	Attempts int
+
+	// The outcome of every Order cancellation, across all attempts.
+	CancelledOrders []OrderCancelResult
+
+	// The outcome of every Position closeout, across all attempts.
+	ClosedPositions []PositionCloseResult
+
+	// The errors of the requests made on the way, including the ones which were later overcome by retrying.
+	Errors []error
+
+	// The number of Trades open in the Account at the last verification.
+	OpenTradeCount int
+
+	// The number of Orders pending in the Account at the last verification.
+	PendingOrderCount int
+
+	// Whether the Account was verified to have no open Trades and no pending Orders.
+	Flat bool
+}
+
+// FlattenAccount cancels all pending Orders and closes all open Positions in an Account, then verifies through the
+// Account summary that no Trades are open and no Orders are pending. Whatever is left over is retried a few times
+// before giving up with ErrAccountNotFlat. It is safe to call on an Account which is already flat, or partially
+// flattened by a previous call.
+//
+// The report is returned even when an error is, it describes everything done up to that point.
+func (c *Client) FlattenAccount(ctx context.Context, accountID AccountID) (*FlattenReport, error) {
+	report := &FlattenReport{}
+	for report.Attempts < flattenAttempts {
+		if report.Attempts > 0 {
+			select {
+			case <-ctx.Done():
+				return report, ctx.Err()
+			case <-time.After(flattenRetryInterval):
+			}
+		}
+		report.Attempts++
+		// Orders are cancelled first so that no entry Order opens a new Trade once the Positions are closed
+		c.flattenOrders(accountID, report)
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+		c.flattenPositions(accountID, report)
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+		summary, err := c.GetAccountSummary(accountID)
+		if err != nil {
+			report.Errors = append(report.Errors, fmt.Errorf("verifying account: %w", err))
+			continue
+		}
+		report.OpenTradeCount = summary.Account.OpenTradeCount
+		report.PendingOrderCount = summary.Account.PendingOrderCount
+		if report.OpenTradeCount == 0 && report.PendingOrderCount == 0 {
+			report.Flat = true
+			return report, nil
+		}
+	}
+	return report, ErrAccountNotFlat
+}
+
+func (c *Client) flattenOrders(accountID AccountID, report *FlattenReport) {
+	cancelled, err := c.CancelAllOrders(accountID, OrderFilter{})
+	if err != nil {
+		report.Errors = append(report.Errors, fmt.Errorf("listing pending orders: %w", err))
+		return
+	}
+	report.CancelledOrders = append(report.CancelledOrders, cancelled.Results...)
+	for _, result := range cancelled.Failed() {
+		report.Errors = append(report.Errors, fmt.Errorf("cancelling order %s: %w", result.OrderID, result.Err))
+	}
+}
+
+func (c *Client) flattenPositions(accountID AccountID, report *FlattenReport) {
+	positions, err := c.GetAccountOpenPositions(accountID)
+	if err != nil {
+		report.Errors = append(report.Errors, fmt.Errorf("listing open positions: %w", err))
+		return
+	}
+	results := make([]PositionCloseResult, len(positions.Positions))
+	forEachConcurrently(len(positions.Positions), func(i int) {
+		position := positions.Positions[i]
+		// Only the open sides are closed, OANDA rejects closing a side of the Position which has no units
+		request := CloseAccountInstrumentPositionRequest{LongUnits: CloseNone, ShortUnits: CloseNone}
+		if !position.Long.Units.IsZero() {
+			request.LongUnits = CloseAll
+		}
+		if !position.Short.Units.IsZero() {
+			request.ShortUnits = CloseAll
+		}
+		response, err := c.CloseAccountInstrumentPosition(accountID, position.Instrument, request)
+		results[i] = PositionCloseResult{Instrument: position.Instrument, Response: response, Err: err}
+	})
+	report.ClosedPositions = append(report.ClosedPositions, results...)
+	for _, result := range results {
+		if result.Err != nil {
+			report.Errors = append(report.Errors, fmt.Errorf("closing position %s: %w", result.Instrument, result.Err))
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package oanda_sdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeAccount is a minimal OANDA server holding pending Orders and open Positions which can be cancelled and closed
+type fakeAccount struct {
+	mu        sync.Mutex
+	orders    []string
+	positions map[string]bool
+	closed    []CloseAccountInstrumentPositionRequest
+}
+
+func (fa *fakeAccount) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	fa.mu.Lock()
+	defer fa.mu.Unlock()
+	path := strings.TrimPrefix(r.URL.Path, "/v3/accounts/001")
+	switch {
+	case path == "/pendingOrders":
+		orders := make([]string, 0, len(fa.orders))
+		for _, id := range fa.orders {
+			orders = append(orders, `{"id": "`+id+`", "type": "LIMIT", "instrument": "EUR_USD", "state": "PENDING"}`)
+		}
+		w.Write([]byte(`{"orders": [` + strings.Join(orders, ",") + `]}`))
+	case strings.HasPrefix(path, "/orders/") && strings.HasSuffix(path, "/cancel"):
+		id := strings.Split(path, "/")[2]
+		for i, order := range fa.orders {
+			if order == id {
+				fa.orders = append(fa.orders[:i], fa.orders[i+1:]...)
+				w.Write([]byte(`{"orderCancelTransaction": {"id": "300", "type": "ORDER_CANCEL", "orderID": "` + id + `"}}`))
+				return
+			}
+		}
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"errorMessage": "The Order specified does not exist"}`))
+	case path == "/openPositions":
+		positions := make([]string, 0, len(fa.positions))
+		for instrument := range fa.positions {
+			positions = append(positions, `{"instrument": "`+instrument+`", "long": {"units": "100"}, "short": {"units": "0"}}`)
+		}
+		w.Write([]byte(`{"positions": [` + strings.Join(positions, ",") + `]}`))
+	case strings.HasPrefix(path, "/positions/") && strings.HasSuffix(path, "/close"):
+		var request CloseAccountInstrumentPositionRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		fa.closed = append(fa.closed, request)
+		delete(fa.positions, strings.Split(path, "/")[2])
+		w.Write([]byte(`{"lastTransactionID": "301"}`))
+	case path == "/summary":
+		fmt.Fprintf(w, `{"account": {"openTradeCount": %d, "pendingOrderCount": %d}}`, len(fa.positions), len(fa.orders))
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+func TestFlattenAccount(t *testing.T) {
+	account := &fakeAccount{
+		orders:    []string{"101", "102"},
+		positions: map[string]bool{"EUR_USD": true},
+	}
+	server := httptest.NewServer(account)
+	defer server.Close()
+	client := NewClient(server.URL, "token", server.Client())
+	report, err := client.FlattenAccount(context.Background(), "001")
+	if err != nil {
+		t.Fatal(err, report.Errors)
+	}
+	if !report.Flat || report.Attempts != 1 || len(report.CancelledOrders) != 2 || len(report.ClosedPositions) != 1 {
+		t.Error("Got ", report)
+	}
+	if len(account.closed) != 1 || account.closed[0].LongUnits != CloseAll || account.closed[0].ShortUnits != CloseNone {
+		t.Error("Got ", account.closed)
+	}
+
+	// Flattening an Account which is already flat only verifies it
+	report, err = client.FlattenAccount(context.Background(), "001")
+	if err != nil || !report.Flat || len(report.CancelledOrders) != 0 || len(report.ClosedPositions) != 0 {
+		t.Error("Got ", report, err)
+	}
+}
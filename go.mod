@@ -1,7 +1,7 @@
 module github.com/czechnorris/oanda-sdk
 
-go 1.21
+go 1.23
 
 require github.com/shopspring/decimal v1.3.1
 
-require github.com/google/go-querystring v1.1.0 // indirect
+require github.com/google/go-querystring v1.1.0
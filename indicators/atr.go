@@ -0,0 +1,61 @@
+package indicators
+
+import (
+	oanda "github.com/czechnorris/oanda-sdk"
+	"github.com/shopspring/decimal"
+)
+
+// ATR is the streaming form of [oanda_sdk.AverageTrueRange]: the true range of each candlestick after the first is
+// averaged with a [oanda_sdk.WilderAverage] of the period
+type ATR struct {
+	ranges   *oanda.WilderAverage
+	previous *decimal.Decimal
+	value    decimal.Decimal
+	ready    bool
+}
+
+// NewATR creates an average true range of the period
+func NewATR(period int) (*ATR, error) {
+	ranges, err := oanda.NewWilderAverage(period)
+	if err != nil {
+		return nil, err
+	}
+	return &ATR{ranges: ranges}, nil
+}
+
+// Add adds the data of a candlestick and returns the average, which is only available once period+1 candlesticks
+// were added
+func (a *ATR) Add(data oanda.CandlestickData) (decimal.Decimal, bool) {
+	previous := a.previous
+	a.previous = &data.Close
+	if previous == nil {
+		return decimal.Zero, false
+	}
+	a.value, a.ready = a.ranges.Add(data.TrueRange(*previous))
+	return a.value, a.ready
+}
+
+// Value returns the current average, which is only available once period+1 candlesticks were added
+func (a *ATR) Value() (decimal.Decimal, bool) {
+	return a.value, a.ready
+}
+
+// ATRSeries computes the average true range of the data of the Input's Price component of the candlesticks, starting
+// at the (period+1)-th one. The Input's Field is not used.
+func ATRSeries(candles []oanda.Candlestick, input Input, period int) ([]Point, error) {
+	atr, err := NewATR(period)
+	if err != nil {
+		return nil, err
+	}
+	var result []Point
+	for _, candle := range candles {
+		data, err := input.Data(candle)
+		if err != nil {
+			return nil, err
+		}
+		if value, ok := atr.Add(data); ok {
+			result = append(result, Point{Time: candle.Time, Value: value})
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,106 @@
+package indicators
+
+import (
+	"errors"
+	oanda "github.com/czechnorris/oanda-sdk"
+	"github.com/shopspring/decimal"
+	"math"
+	"time"
+)
+
+// Bands are the values of the Bollinger Bands
+type Bands struct {
+	// The simple moving average plus the width times the standard deviation.
+	Upper decimal.Decimal
+
+	// The simple moving average.
+	Middle decimal.Decimal
+
+	// The simple moving average minus the width times the standard deviation.
+	Lower decimal.Decimal
+}
+
+// BandsPoint is the value of the Bollinger Bands at the time of a candlestick
+type BandsPoint struct {
+	Time time.Time
+	Bands
+}
+
+// BollingerBands are the bands around the simple moving average of the values at a number of (population) standard
+// deviations of the values, typically with the period 20 and width 2
+type BollingerBands struct {
+	sma   *SMA
+	width decimal.Decimal
+	value Bands
+	ready bool
+}
+
+// NewBollingerBands creates Bollinger Bands of the period, width standard deviations from the average
+func NewBollingerBands(period int, width decimal.Decimal) (*BollingerBands, error) {
+	if !width.IsPositive() {
+		return nil, errors.New("width must be positive")
+	}
+	sma, err := NewSMA(period)
+	if err != nil {
+		return nil, err
+	}
+	return &BollingerBands{sma: sma, width: width}, nil
+}
+
+// Add adds a value and returns the bands, which are only available once period values were added
+func (b *BollingerBands) Add(value decimal.Decimal) (Bands, bool) {
+	mean, ok := b.sma.Add(value)
+	if !ok {
+		return Bands{}, false
+	}
+	variance := decimal.Zero
+	for _, v := range b.sma.window {
+		deviation := v.Sub(mean)
+		variance = variance.Add(deviation.Mul(deviation))
+	}
+	variance = variance.Div(decimal.NewFromInt(int64(b.sma.period)))
+	offset := sqrt(variance).Mul(b.width)
+	b.value, b.ready = Bands{Upper: mean.Add(offset), Middle: mean, Lower: mean.Sub(offset)}, true
+	return b.value, true
+}
+
+// Value returns the current bands, which are only available once period values were added
+func (b *BollingerBands) Value() (Bands, bool) {
+	return b.value, b.ready
+}
+
+// BollingerBandsSeries computes the Bollinger Bands of the candlesticks, starting at the period-th one
+func BollingerBandsSeries(candles []oanda.Candlestick, input Input, period int, width decimal.Decimal) ([]BandsPoint, error) {
+	bands, err := NewBollingerBands(period, width)
+	if err != nil {
+		return nil, err
+	}
+	values, times, err := series(candles, input, bands.Add)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]BandsPoint, len(values))
+	for i := range values {
+		result[i] = BandsPoint{Time: times[i], Bands: values[i]}
+	}
+	return result, nil
+}
+
+// sqrt returns the square root of a non-negative decimal rounded to decimal.DivisionPrecision digits, refining the
+// floating point estimate with Newton's method
+func sqrt(d decimal.Decimal) decimal.Decimal {
+	if !d.IsPositive() {
+		return decimal.Zero
+	}
+	two := decimal.NewFromInt(2)
+	precision := int32(decimal.DivisionPrecision)
+	x := decimal.NewFromFloat(math.Sqrt(d.InexactFloat64()))
+	for i := 0; i < 100; i++ {
+		next := x.Add(d.DivRound(x, precision+4)).Div(two).Round(precision + 4)
+		if next.Equal(x) {
+			break
+		}
+		x = next
+	}
+	return x.Round(precision)
+}
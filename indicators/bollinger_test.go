@@ -0,0 +1,41 @@
+package indicators
+
+import (
+	"github.com/shopspring/decimal"
+	"testing"
+)
+
+func TestBollingerBandsSeries(t *testing.T) {
+	points, err := BollingerBandsSeries(closes("2", "4", "4", "4", "5", "5", "7", "9"), Input{}, 8, decimal.NewFromInt(2))
+	if err != nil {
+		t.Fatal("Got ", err)
+	}
+	if len(points) != 1 {
+		t.Fatal("Got ", points)
+	}
+	bands := points[0].Bands
+	if !bands.Middle.Equal(decimal.NewFromInt(5)) || !bands.Upper.Equal(decimal.NewFromInt(9)) || !bands.Lower.Equal(decimal.NewFromInt(1)) {
+		t.Error("Got ", bands)
+	}
+}
+
+func TestBollingerBandsFlat(t *testing.T) {
+	bands, err := NewBollingerBands(2, decimal.NewFromInt(2))
+	if err != nil {
+		t.Fatal("Got ", err)
+	}
+	bands.Add(decimal.NewFromInt(1))
+	value, ok := bands.Add(decimal.NewFromInt(1))
+	if !ok || !value.Upper.Equal(decimal.NewFromInt(1)) || !value.Lower.Equal(decimal.NewFromInt(1)) {
+		t.Error("Got ", value, ok)
+	}
+}
+
+func TestSqrt(t *testing.T) {
+	if root := sqrt(decimal.NewFromInt(2)); !root.Equal(decimal.RequireFromString("1.414213562373095")) {
+		t.Error("Got ", root)
+	}
+	if root := sqrt(decimal.RequireFromString("0.0001")); !root.Equal(decimal.RequireFromString("0.01")) {
+		t.Error("Got ", root)
+	}
+}
@@ -0,0 +1,137 @@
+// Package indicators implements technical indicators (SMA, EMA, RSI, ATR, MACD, Bollinger Bands and VWAP) over
+// candlesticks with decimal arithmetic.
+//
+// Every indicator has a streaming version, which is fed one value or candlestick at a time and reports whether it has
+// seen enough of them to produce a value, and a batch version computing the indicator over a []Candlestick. Sums and
+// differences are exact, quotients (and the square roots of the Bollinger Bands) are rounded to
+// decimal.DivisionPrecision digits.
+package indicators
+
+import (
+	"errors"
+	"fmt"
+	oanda "github.com/czechnorris/oanda-sdk"
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+// Field is the price of a candlestick an indicator is computed from
+type Field int
+
+const (
+	// Close is the closing price
+	Close Field = iota
+
+	// Open is the opening price
+	Open
+
+	// High is the highest price
+	High
+
+	// Low is the lowest price
+	Low
+
+	// Median is the average of the high and low prices
+	Median
+
+	// Typical is the average of the high, low and closing prices
+	Typical
+
+	// Weighted is the average of the high, low and twice the closing price
+	Weighted
+)
+
+// Input selects the price the indicators are computed from
+type Input struct {
+	// The Price component of the candlesticks: "M", "B" or "A". Default: M
+	Component oanda.PricingComponent
+
+	// The price of the candlestick data. Default: Close
+	Field Field
+}
+
+// Value returns the price of the candlestick data selected by the Input
+func (i Input) Value(data oanda.CandlestickData) decimal.Decimal {
+	switch i.Field {
+	case Open:
+		return data.Open
+	case High:
+		return data.High
+	case Low:
+		return data.Low
+	case Median:
+		return data.High.Add(data.Low).Div(decimal.NewFromInt(2))
+	case Typical:
+		return data.High.Add(data.Low).Add(data.Close).Div(decimal.NewFromInt(3))
+	case Weighted:
+		return data.High.Add(data.Low).Add(data.Close.Mul(decimal.NewFromInt(2))).Div(decimal.NewFromInt(4))
+	default:
+		return data.Close
+	}
+}
+
+// Data returns the candlestick data of the Input's Price component (see [oanda_sdk.Candlestick.Data])
+func (i Input) Data(candle oanda.Candlestick) (oanda.CandlestickData, error) {
+	component := i.Component
+	if component == "" {
+		component = "M"
+	}
+	data, ok := candle.Data(component)
+	if !ok {
+		return oanda.CandlestickData{}, fmt.Errorf("candlestick at %s has no %s data", candle.Time.Format(time.RFC3339), component)
+	}
+	return data, nil
+}
+
+// Point is the value of an indicator at the time of a candlestick
+type Point struct {
+	Time  time.Time
+	Value decimal.Decimal
+}
+
+// Complete returns the complete candlesticks, leaving out the ones still forming. The batch indicators use every
+// candlestick they are given, so the candlesticks should be passed through Complete to compute the indicators of
+// closed candlesticks only.
+func Complete(candles []oanda.Candlestick) []oanda.Candlestick {
+	var complete []oanda.Candlestick
+	for _, candle := range candles {
+		if candle.Complete {
+			complete = append(complete, candle)
+		}
+	}
+	return complete
+}
+
+// series feeds the Input's price of each candlestick to add and collects the values it produces
+func series[T any](candles []oanda.Candlestick, input Input, add func(decimal.Decimal) (T, bool)) ([]T, []time.Time, error) {
+	var values []T
+	var times []time.Time
+	for _, candle := range candles {
+		data, err := input.Data(candle)
+		if err != nil {
+			return nil, nil, err
+		}
+		if value, ok := add(input.Value(data)); ok {
+			values = append(values, value)
+			times = append(times, candle.Time)
+		}
+	}
+	return values, times, nil
+}
+
+// points pairs the values with their times
+func points(values []decimal.Decimal, times []time.Time) []Point {
+	result := make([]Point, len(values))
+	for i := range values {
+		result[i] = Point{Time: times[i], Value: values[i]}
+	}
+	return result
+}
+
+// checkPeriod returns an error when the period is not positive
+func checkPeriod(period int) error {
+	if period <= 0 {
+		return errors.New("period must be positive")
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package indicators
+
+import (
+	oanda "github.com/czechnorris/oanda-sdk"
+	"github.com/shopspring/decimal"
+	"testing"
+	"time"
+)
+
+func candleData(high, low, close string) oanda.CandlestickData {
+	return oanda.CandlestickData{
+		Open:  decimal.RequireFromString(close),
+		High:  decimal.RequireFromString(high),
+		Low:   decimal.RequireFromString(low),
+		Close: decimal.RequireFromString(close),
+	}
+}
+
+func TestInput(t *testing.T) {
+	data := candleData("3", "1", "2.5")
+	if value := (Input{Field: Typical}).Value(data); !value.Equal(decimal.RequireFromString("2.1666666666666667")) {
+		t.Error("Got ", value)
+	}
+	if value := (Input{Field: Median}).Value(data); !value.Equal(decimal.NewFromInt(2)) {
+		t.Error("Got ", value)
+	}
+	if value := (Input{Field: Weighted}).Value(data); !value.Equal(decimal.RequireFromString("2.25")) {
+		t.Error("Got ", value)
+	}
+
+	bid, ask := candleData("1.1", "1.0", "1.05"), candleData("1.3", "1.2", "1.25")
+	candle := oanda.Candlestick{Bid: &bid, Ask: &ask}
+	if mid, err := (Input{}).Data(candle); err != nil || !mid.Close.Equal(decimal.RequireFromString("1.15")) {
+		t.Error("Got ", mid, err)
+	}
+	if _, err := (Input{Component: "B"}).Data(oanda.Candlestick{Mid: &bid}); err == nil {
+		t.Error("Got no error for missing bid data")
+	}
+}
+
+func TestRSISeries(t *testing.T) {
+	points, err := RSISeries(closes("1", "2", "3", "2", "3"), Input{}, 2)
+	if err != nil {
+		t.Fatal("Got ", err)
+	}
+	expectPoints(t, points, "100", "50", "75")
+
+	points, err = RSISeries(closes("1", "1", "1"), Input{}, 2)
+	if err != nil {
+		t.Fatal("Got ", err)
+	}
+	expectPoints(t, points, "50")
+}
+
+func TestATRSeries(t *testing.T) {
+	data := []oanda.CandlestickData{
+		candleData("2", "1", "1.5"),
+		candleData("3", "2", "2.5"),
+		candleData("2.6", "2.4", "2.5"),
+		candleData("4", "3", "3.5"),
+	}
+	candles := make([]oanda.Candlestick, len(data))
+	for i := range data {
+		candles[i] = oanda.Candlestick{Time: start.Add(time.Duration(i) * time.Minute), Mid: &data[i], Complete: true}
+	}
+	points, err := ATRSeries(candles, Input{}, 2)
+	if err != nil {
+		t.Fatal("Got ", err)
+	}
+	expectPoints(t, points, "0.85", "1.175")
+
+	atr, err := oanda.AverageTrueRange(candles, 2)
+	if err != nil || !atr.Equal(points[1].Value) {
+		t.Error("Got ", atr, err)
+	}
+}
+
+func TestMACDSeries(t *testing.T) {
+	candles := closes("1", "2", "3", "6", "3", "4", "8", "7", "5")
+	points, err := MACDSeries(candles, Input{}, 2, 3, 2)
+	if err != nil {
+		t.Fatal("Got ", err)
+	}
+	// The signal needs two MACD values, which start at the third candlestick
+	if len(points) != len(candles)-3 {
+		t.Fatal("Got ", points)
+	}
+	fast, _ := EMASeries(candles, Input{}, 2)
+	slow, _ := EMASeries(candles, Input{}, 3)
+	for i, point := range points {
+		macd := fast[i+2].Value.Sub(slow[i+1].Value)
+		if !point.MACD.Equal(macd) || !point.Histogram.Equal(point.MACD.Sub(point.Signal)) {
+			t.Error("Got ", i, " ", point.MACDValue)
+		}
+		if !point.Time.Equal(candles[i+3].Time) {
+			t.Error("Got ", point.Time)
+		}
+	}
+	if _, err := NewMACD(26, 12, 9); err == nil {
+		t.Error("Got no error for a fast period longer than the slow one")
+	}
+}
+
+func TestVWAPSeries(t *testing.T) {
+	candles := []oanda.Candlestick{
+		{Time: time.Date(2024, 6, 3, 19, 30, 0, 0, time.UTC), Mid: &oanda.CandlestickData{Close: decimal.NewFromInt(9)}},
+		{Time: time.Date(2024, 6, 3, 20, 0, 0, 0, time.UTC), Mid: &oanda.CandlestickData{Close: decimal.NewFromInt(1)}, Volume: 1},
+		{Time: time.Date(2024, 6, 3, 20, 30, 0, 0, time.UTC), Mid: &oanda.CandlestickData{Close: decimal.NewFromInt(2)}, Volume: 3},
+		// The trading day starts at 17:00 in New York
+		{Time: time.Date(2024, 6, 3, 21, 0, 0, 0, time.UTC), Mid: &oanda.CandlestickData{Close: decimal.NewFromInt(4)}, Volume: 2},
+	}
+	points, err := VWAPSeries(candles, Input{}, oanda.D)
+	if err != nil {
+		t.Fatal("Got ", err)
+	}
+	expectPoints(t, points, "1", "1.75", "4")
+
+	points, err = VWAPSeries(candles, Input{}, "")
+	if err != nil {
+		t.Fatal("Got ", err)
+	}
+	expectPoints(t, points, "1", "1.75", "2.5")
+}
+
+func TestComplete(t *testing.T) {
+	candles := closes("1", "2")
+	candles[1].Complete = false
+	if complete := Complete(candles); len(complete) != 1 || !complete[0].Time.Equal(start) {
+		t.Error("Got ", complete)
+	}
+}
@@ -0,0 +1,93 @@
+package indicators
+
+import (
+	"fmt"
+	oanda "github.com/czechnorris/oanda-sdk"
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+// MACDValue is a value of the MACD
+type MACDValue struct {
+	// The difference between the fast and the slow exponential moving average.
+	MACD decimal.Decimal
+
+	// The exponential moving average of the MACD.
+	Signal decimal.Decimal
+
+	// The difference between the MACD and the Signal.
+	Histogram decimal.Decimal
+}
+
+// MACDPoint is the value of the MACD at the time of a candlestick
+type MACDPoint struct {
+	Time time.Time
+	MACDValue
+}
+
+// MACD is the moving average convergence/divergence of the values, typically with the periods 12, 26 and 9
+type MACD struct {
+	fast   *EMA
+	slow   *EMA
+	signal *EMA
+	value  MACDValue
+	ready  bool
+}
+
+// NewMACD creates a MACD of the fast and slow exponential moving averages, with a signal line of the signal period
+func NewMACD(fast, slow, signal int) (*MACD, error) {
+	if fast >= slow {
+		return nil, fmt.Errorf("fast period %d must be shorter than slow period %d", fast, slow)
+	}
+	fastEMA, err := NewEMA(fast)
+	if err != nil {
+		return nil, err
+	}
+	slowEMA, err := NewEMA(slow)
+	if err != nil {
+		return nil, err
+	}
+	signalEMA, err := NewEMA(signal)
+	if err != nil {
+		return nil, err
+	}
+	return &MACD{fast: fastEMA, slow: slowEMA, signal: signalEMA}, nil
+}
+
+// Add adds a value and returns the MACD, which is only available once slow+signal-1 values were added
+func (m *MACD) Add(value decimal.Decimal) (MACDValue, bool) {
+	fast, _ := m.fast.Add(value)
+	slow, ok := m.slow.Add(value)
+	if !ok {
+		return MACDValue{}, false
+	}
+	macd := fast.Sub(slow)
+	signal, ok := m.signal.Add(macd)
+	if !ok {
+		return MACDValue{}, false
+	}
+	m.value, m.ready = MACDValue{MACD: macd, Signal: signal, Histogram: macd.Sub(signal)}, true
+	return m.value, true
+}
+
+// Value returns the current MACD, which is only available once slow+signal-1 values were added
+func (m *MACD) Value() (MACDValue, bool) {
+	return m.value, m.ready
+}
+
+// MACDSeries computes the MACD of the candlesticks, starting at the (slow+signal-1)-th one
+func MACDSeries(candles []oanda.Candlestick, input Input, fast, slow, signal int) ([]MACDPoint, error) {
+	macd, err := NewMACD(fast, slow, signal)
+	if err != nil {
+		return nil, err
+	}
+	values, times, err := series(candles, input, macd.Add)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]MACDPoint, len(values))
+	for i := range values {
+		result[i] = MACDPoint{Time: times[i], MACDValue: values[i]}
+	}
+	return result, nil
+}
@@ -0,0 +1,102 @@
+package indicators
+
+import (
+	oanda "github.com/czechnorris/oanda-sdk"
+	"github.com/shopspring/decimal"
+)
+
+// SMA is the simple moving average of the last period values
+type SMA struct {
+	period int
+	window []decimal.Decimal
+	next   int
+	sum    decimal.Decimal
+}
+
+// NewSMA creates a simple moving average of the period
+func NewSMA(period int) (*SMA, error) {
+	if err := checkPeriod(period); err != nil {
+		return nil, err
+	}
+	return &SMA{period: period, window: make([]decimal.Decimal, 0, period)}, nil
+}
+
+// Add adds a value and returns the average, which is only available once period values were added
+func (s *SMA) Add(value decimal.Decimal) (decimal.Decimal, bool) {
+	if len(s.window) < s.period {
+		s.window = append(s.window, value)
+	} else {
+		s.sum = s.sum.Sub(s.window[s.next])
+		s.window[s.next] = value
+		s.next = (s.next + 1) % s.period
+	}
+	s.sum = s.sum.Add(value)
+	return s.Value()
+}
+
+// Value returns the current average, which is only available once period values were added
+func (s *SMA) Value() (decimal.Decimal, bool) {
+	if len(s.window) < s.period {
+		return decimal.Zero, false
+	}
+	return s.sum.Div(decimal.NewFromInt(int64(s.period))), true
+}
+
+// SMASeries computes the simple moving average of the candlesticks, starting at the period-th one
+func SMASeries(candles []oanda.Candlestick, input Input, period int) ([]Point, error) {
+	sma, err := NewSMA(period)
+	if err != nil {
+		return nil, err
+	}
+	values, times, err := series(candles, input, sma.Add)
+	if err != nil {
+		return nil, err
+	}
+	return points(values, times), nil
+}
+
+// EMA is the exponential moving average of the values, weighting each new value by 2/(period+1). It is seeded with the
+// simple moving average of the first period values.
+type EMA struct {
+	period int
+	seed   *SMA
+	value  decimal.Decimal
+	ready  bool
+}
+
+// NewEMA creates an exponential moving average of the period
+func NewEMA(period int) (*EMA, error) {
+	seed, err := NewSMA(period)
+	if err != nil {
+		return nil, err
+	}
+	return &EMA{period: period, seed: seed}, nil
+}
+
+// Add adds a value and returns the average, which is only available once period values were added
+func (e *EMA) Add(value decimal.Decimal) (decimal.Decimal, bool) {
+	if !e.ready {
+		e.value, e.ready = e.seed.Add(value)
+		return e.value, e.ready
+	}
+	e.value = e.value.Add(value.Sub(e.value).Mul(decimal.NewFromInt(2)).Div(decimal.NewFromInt(int64(e.period + 1))))
+	return e.value, true
+}
+
+// Value returns the current average, which is only available once period values were added
+func (e *EMA) Value() (decimal.Decimal, bool) {
+	return e.value, e.ready
+}
+
+// EMASeries computes the exponential moving average of the candlesticks, starting at the period-th one
+func EMASeries(candles []oanda.Candlestick, input Input, period int) ([]Point, error) {
+	ema, err := NewEMA(period)
+	if err != nil {
+		return nil, err
+	}
+	values, times, err := series(candles, input, ema.Add)
+	if err != nil {
+		return nil, err
+	}
+	return points(values, times), nil
+}
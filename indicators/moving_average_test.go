@@ -0,0 +1,74 @@
+package indicators
+
+import (
+	oanda "github.com/czechnorris/oanda-sdk"
+	"github.com/shopspring/decimal"
+	"testing"
+	"time"
+)
+
+var start = time.Date(2024, 6, 3, 12, 0, 0, 0, time.UTC)
+
+// closes creates complete mid candlesticks one minute apart with the closing prices
+func closes(values ...string) []oanda.Candlestick {
+	candles := make([]oanda.Candlestick, len(values))
+	for i, value := range values {
+		price := decimal.RequireFromString(value)
+		candles[i] = oanda.Candlestick{
+			Time:     start.Add(time.Duration(i) * time.Minute),
+			Mid:      &oanda.CandlestickData{Open: price, High: price, Low: price, Close: price},
+			Volume:   1,
+			Complete: true,
+		}
+	}
+	return candles
+}
+
+func expectPoints(t *testing.T, points []Point, expected ...string) {
+	t.Helper()
+	if len(points) != len(expected) {
+		t.Fatal("Got ", points)
+	}
+	for i, point := range points {
+		if !point.Value.Equal(decimal.RequireFromString(expected[i])) {
+			t.Error("Got ", i, " ", point.Value)
+		}
+	}
+}
+
+func TestSMASeries(t *testing.T) {
+	points, err := SMASeries(closes("1", "2", "3", "4", "5"), Input{}, 3)
+	if err != nil {
+		t.Fatal("Got ", err)
+	}
+	expectPoints(t, points, "2", "3", "4")
+	if !points[0].Time.Equal(start.Add(2 * time.Minute)) {
+		t.Error("Got ", points[0].Time)
+	}
+}
+
+func TestSMAStreaming(t *testing.T) {
+	sma, err := NewSMA(2)
+	if err != nil {
+		t.Fatal("Got ", err)
+	}
+	if _, ok := sma.Add(decimal.NewFromInt(1)); ok {
+		t.Error("Got a value before the period")
+	}
+	sma.Add(decimal.NewFromInt(2))
+	sma.Add(decimal.RequireFromString("0.1"))
+	if value, ok := sma.Value(); !ok || !value.Equal(decimal.RequireFromString("1.05")) {
+		t.Error("Got ", value, ok)
+	}
+	if _, err := NewSMA(0); err == nil {
+		t.Error("Got no error for a zero period")
+	}
+}
+
+func TestEMASeries(t *testing.T) {
+	points, err := EMASeries(closes("1", "2", "3", "6", "3"), Input{}, 3)
+	if err != nil {
+		t.Fatal("Got ", err)
+	}
+	expectPoints(t, points, "2", "4", "3.5")
+}
@@ -0,0 +1,68 @@
+package indicators
+
+import (
+	oanda "github.com/czechnorris/oanda-sdk"
+	"github.com/shopspring/decimal"
+)
+
+// RSI is Wilder's relative strength index of the values, between 0 and 100. The gains and losses between consecutive
+// values are averaged with a [oanda_sdk.WilderAverage] of the period.
+type RSI struct {
+	gains    *oanda.WilderAverage
+	losses   *oanda.WilderAverage
+	previous *decimal.Decimal
+	value    decimal.Decimal
+	ready    bool
+}
+
+// NewRSI creates a relative strength index of the period
+func NewRSI(period int) (*RSI, error) {
+	gains, err := oanda.NewWilderAverage(period)
+	if err != nil {
+		return nil, err
+	}
+	losses, _ := oanda.NewWilderAverage(period)
+	return &RSI{gains: gains, losses: losses}, nil
+}
+
+// Add adds a value and returns the index, which is only available once period+1 values were added. The index is 50
+// when the values did not change over the period.
+func (r *RSI) Add(value decimal.Decimal) (decimal.Decimal, bool) {
+	previous := r.previous
+	r.previous = &value
+	if previous == nil {
+		return decimal.Zero, false
+	}
+	change := value.Sub(*previous)
+	gain, _ := r.gains.Add(decimal.Max(change, decimal.Zero))
+	loss, ready := r.losses.Add(decimal.Max(change.Neg(), decimal.Zero))
+	if !ready {
+		return decimal.Zero, false
+	}
+	hundred := decimal.NewFromInt(100)
+	if total := gain.Add(loss); total.IsZero() {
+		r.value = hundred.Div(decimal.NewFromInt(2))
+	} else {
+		r.value = hundred.Mul(gain).Div(total)
+	}
+	r.ready = true
+	return r.value, true
+}
+
+// Value returns the current index, which is only available once period+1 values were added
+func (r *RSI) Value() (decimal.Decimal, bool) {
+	return r.value, r.ready
+}
+
+// RSISeries computes the relative strength index of the candlesticks, starting at the (period+1)-th one
+func RSISeries(candles []oanda.Candlestick, input Input, period int) ([]Point, error) {
+	rsi, err := NewRSI(period)
+	if err != nil {
+		return nil, err
+	}
+	values, times, err := series(candles, input, rsi.Add)
+	if err != nil {
+		return nil, err
+	}
+	return points(values, times), nil
+}
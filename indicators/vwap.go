@@ -0,0 +1,70 @@
+package indicators
+
+import (
+	oanda "github.com/czechnorris/oanda-sdk"
+	"github.com/shopspring/decimal"
+)
+
+// VWAP is the volume-weighted average price of the values since it was created or last reset. The volume of OANDA's
+// candlesticks is their number of price updates (tick volume).
+type VWAP struct {
+	weighted decimal.Decimal
+	volume   int64
+}
+
+// NewVWAP creates a volume-weighted average price
+func NewVWAP() *VWAP {
+	return &VWAP{}
+}
+
+// Add adds a price with its volume and returns the average, which is only available once some volume was added
+func (v *VWAP) Add(price decimal.Decimal, volume int) (decimal.Decimal, bool) {
+	v.weighted = v.weighted.Add(price.Mul(decimal.NewFromInt(int64(volume))))
+	v.volume += int64(volume)
+	return v.Value()
+}
+
+// Value returns the current average, which is only available once some volume was added
+func (v *VWAP) Value() (decimal.Decimal, bool) {
+	if v.volume == 0 {
+		return decimal.Zero, false
+	}
+	return v.weighted.Div(decimal.NewFromInt(v.volume)), true
+}
+
+// Reset starts a new average, e.g. at the start of a trading session
+func (v *VWAP) Reset() {
+	v.weighted, v.volume = decimal.Zero, 0
+}
+
+// VWAPSeries computes the volume-weighted average price of the candlesticks, weighting the Input's price (customarily
+// the Typical one) by their volume. The average is reset at the start of each candlestick of the session granularity
+// (e.g. D for a daily VWAP), aligned with the default alignment of GetInstrumentCandles, or never when the session is
+// empty. Candlesticks are skipped until some volume was added.
+func VWAPSeries(candles []oanda.Candlestick, input Input, session oanda.CandlestickGranularity) ([]Point, error) {
+	var aligner *oanda.CandleAligner
+	if session != "" {
+		var err error
+		if aligner, err = oanda.NewCandleAligner(session, oanda.CandleAlignment{}); err != nil {
+			return nil, err
+		}
+	}
+	vwap := NewVWAP()
+	var result []Point
+	var current *oanda.TimeRange
+	for _, candle := range candles {
+		data, err := input.Data(candle)
+		if err != nil {
+			return nil, err
+		}
+		if aligner != nil && (current == nil || !candle.Time.Before(current.To)) {
+			period := aligner.Period(candle.Time)
+			current = &period
+			vwap.Reset()
+		}
+		if value, ok := vwap.Add(input.Value(data), candle.Volume); ok {
+			result = append(result, Point{Time: candle.Time, Value: value})
+		}
+	}
+	return result, nil
+}
@@ -73,6 +73,21 @@ const (
 	M = CandlestickGranularity("M")
 )
 
+var granularityDurations = map[CandlestickGranularity]time.Duration{
+	S5: 5 * time.Second, S10: 10 * time.Second, S15: 15 * time.Second, S30: 30 * time.Second,
+	M1: time.Minute, M2: 2 * time.Minute, M4: 4 * time.Minute, M5: 5 * time.Minute,
+	M10: 10 * time.Minute, M15: 15 * time.Minute, M30: 30 * time.Minute,
+	H1: time.Hour, H2: 2 * time.Hour, H3: 3 * time.Hour, H4: 4 * time.Hour,
+	H6: 6 * time.Hour, H8: 8 * time.Hour, H12: 12 * time.Hour,
+	D: 24 * time.Hour, W: 7 * 24 * time.Hour, M: 31 * 24 * time.Hour,
+}
+
+// Duration returns the time-range covered by a candlestick of the granularity. Monthly candlesticks vary in length,
+// the length of the longest month is returned for them. Zero is returned for an unknown granularity.
+func (cg CandlestickGranularity) Duration() time.Duration {
+	return granularityDurations[cg]
+}
+
 // WeeklyAlignment represents the day of the week to use for candlestick granularities with weekly alignment
 type WeeklyAlignment string
 
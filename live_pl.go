@@ -0,0 +1,220 @@
+package oanda_sdk
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultLivePLRefreshInterval is the default interval at which a LivePL reloads the open Trades of its Account
+const DefaultLivePLRefreshInterval = time.Minute
+
+// LivePLUpdate is the unrealized profit/loss of the open Trades of an instrument, recomputed for a new Price
+type LivePLUpdate struct {
+	// The date/time of the Price the profit/loss was computed for.
+	Time time.Time
+
+	// The instrument of the Trades.
+	Instrument string
+
+	// The unrealized profit/loss of each open Trade of the instrument, in the home currency. The MarginUsed of the
+	// states is not computed.
+	Trades []CalculatedTradeState
+
+	// The unrealized profit/loss of the Position of the instrument, in the home currency. The MarginUsed of the state
+	// is not computed.
+	Position CalculatedPositionState
+}
+
+// LivePL keeps the unrealized profit/loss of the open Trades of an Account up to date with the pricing stream. Long
+// Trades are valued at the bid and short Trades at the ask, the profit/loss is converted into the home currency with
+// the home conversions of the quote currency.
+//
+// The open Trades and home conversions are reloaded every RefreshInterval, the pricing stream is reopened whenever the
+// instruments of the open Trades change.
+type LivePL struct {
+	// The interval at which the open Trades are reloaded. Default (also used when not positive):
+	// DefaultLivePLRefreshInterval
+	RefreshInterval time.Duration
+
+	client    *Client
+	accountID AccountID
+	updates   chan LivePLUpdate
+
+	mu          sync.Mutex
+	trades      map[string][]Trade
+	conversions map[Currency]HomeConversions
+}
+
+// NewLivePL creates a LivePL for the open Trades of an Account
+func NewLivePL(client *Client, accountID AccountID) *LivePL {
+	return &LivePL{
+		RefreshInterval: DefaultLivePLRefreshInterval,
+		client:          client,
+		accountID:       accountID,
+		updates:         make(chan LivePLUpdate, 64),
+		trades:          make(map[string][]Trade),
+		conversions:     make(map[Currency]HomeConversions),
+	}
+}
+
+// Updates returns the channel the LivePL publishes an update on for every Price of an instrument with open Trades.
+// The channel must be received from while the LivePL runs, Run blocks until the updates are received.
+func (lp *LivePL) Updates() <-chan LivePLUpdate {
+	return lp.updates
+}
+
+// Run loads the open Trades of the Account and recomputes their unrealized profit/loss for each Price from the pricing
+// stream until the context is done, the pricing stream is closed or the open Trades cannot be reloaded
+func (lp *LivePL) Run(ctx context.Context) error {
+	interval := lp.RefreshInterval
+	if interval <= 0 {
+		interval = DefaultLivePLRefreshInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	var instruments []string
+	var prices <-chan ClientPrice
+	stopStream := func() {}
+	defer func() { stopStream() }()
+	for {
+		current, err := lp.refresh(ctx)
+		if err != nil {
+			return err
+		}
+		if !slices.Equal(current, instruments) {
+			stopStream()
+			instruments, prices, stopStream = current, nil, func() {}
+			if len(instruments) > 0 {
+				prices, stopStream, err = lp.client.openPricingStream(ctx, lp.accountID, instruments)
+				if err != nil {
+					return err
+				}
+			}
+		}
+		if err := lp.consume(ctx, prices, ticker.C); err != nil {
+			return err
+		}
+	}
+}
+
+// consume handles the Prices from the stream until the next refresh is due
+func (lp *LivePL) consume(ctx context.Context, prices <-chan ClientPrice, refresh <-chan time.Time) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-refresh:
+			return nil
+		case price, ok := <-prices:
+			if !ok {
+				return errors.New("pricing stream closed")
+			}
+			if err := lp.publish(ctx, price); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (lp *LivePL) publish(ctx context.Context, price ClientPrice) error {
+	update, ok := lp.HandlePrice(price)
+	if !ok {
+		return nil
+	}
+	select {
+	case lp.updates <- update:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// refresh reloads the open Trades and the home conversions, publishes the profit/loss at the current prices and
+// returns the sorted instruments of the open Trades
+func (lp *LivePL) refresh(ctx context.Context) ([]string, error) {
+	response, err := lp.client.GetAccountOpenTrades(lp.accountID)
+	if err != nil {
+		return nil, err
+	}
+	trades := make(map[string][]Trade)
+	for _, trade := range response.Trades {
+		trades[trade.Instrument] = append(trades[trade.Instrument], trade)
+	}
+	instruments := make([]string, 0, len(trades))
+	for instrument := range trades {
+		instruments = append(instruments, instrument)
+	}
+	slices.Sort(instruments)
+
+	var pricing *GetAccountPricingResponse
+	if len(instruments) > 0 {
+		includeHomeConversion := true
+		pricing, err = lp.client.GetAccountPricing(lp.accountID, GetAccountPricingRequest{
+			Instruments:           instruments,
+			IncludeHomeConversion: &includeHomeConversion,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	lp.mu.Lock()
+	lp.trades = trades
+	if pricing != nil {
+		for _, conversion := range pricing.HomeConversions {
+			lp.conversions[conversion.Currency] = conversion
+		}
+	}
+	lp.mu.Unlock()
+
+	if pricing != nil {
+		for _, price := range pricing.Prices {
+			if err := lp.publish(ctx, price); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return instruments, nil
+}
+
+// HandlePrice recomputes the unrealized profit/loss of the open Trades of the Price's instrument. The second return
+// value is false when there are no open Trades of the instrument, the Price lacks liquidity on a side needed to value
+// them, or the quote currency of the instrument has no known home conversion.
+func (lp *LivePL) HandlePrice(price ClientPrice) (LivePLUpdate, bool) {
+	lp.mu.Lock()
+	defer lp.mu.Unlock()
+	trades := lp.trades[price.Instrument]
+	if len(trades) == 0 {
+		return LivePLUpdate{}, false
+	}
+	_, quote, _ := strings.Cut(price.Instrument, "_")
+	conversion, ok := lp.conversions[Currency(quote)]
+	if !ok {
+		return LivePLUpdate{}, false
+	}
+	update := LivePLUpdate{
+		Time:       price.Time,
+		Instrument: price.Instrument,
+		Trades:     make([]CalculatedTradeState, 0, len(trades)),
+		Position:   CalculatedPositionState{Instrument: price.Instrument},
+	}
+	for _, trade := range trades {
+		closePrice, ok := price.ClosingPrice(trade.CurrentUnits)
+		if !ok {
+			return LivePLUpdate{}, false
+		}
+		pl := conversion.ConvertPL(trade.CurrentUnits.Mul(closePrice.Sub(trade.Price)))
+		update.Trades = append(update.Trades, CalculatedTradeState{ID: trade.Id, UnrealizedPL: pl})
+		if trade.CurrentUnits.IsNegative() {
+			update.Position.ShortUnrealizedPL = update.Position.ShortUnrealizedPL.Add(pl)
+		} else {
+			update.Position.LongUnrealizedPL = update.Position.LongUnrealizedPL.Add(pl)
+		}
+	}
+	update.Position.NetUnrealizedPL = update.Position.LongUnrealizedPL.Add(update.Position.ShortUnrealizedPL)
+	return update, true
+}
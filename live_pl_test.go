@@ -0,0 +1,71 @@
+package oanda_sdk
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestLivePLPublishesUpdatesFromPricingStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v3/accounts/001/openTrades":
+			fmt.Fprint(w, `{"trades": [
+				{"id": "1", "instrument": "EUR_USD", "price": "1.1000", "currentUnits": "100"},
+				{"id": "2", "instrument": "EUR_USD", "price": "1.1050", "currentUnits": "-50"}
+			]}`)
+		case "/v3/accounts/001/pricing":
+			fmt.Fprint(w, `{
+				"prices": [{"instrument": "EUR_USD", "bids": [{"price": "1.1010"}], "asks": [{"price": "1.1012"}]}],
+				"homeConversions": [{"currency": "USD", "accountGain": "0.5", "accountLoss": "0.6", "positionValue": "0.55"}]
+			}`)
+		case "/v3/accounts/001/pricing/stream":
+			if r.URL.Query().Get("instruments") != "EUR_USD" {
+				t.Error("Got ", r.URL.RawQuery)
+			}
+			fmt.Fprint(w, `{"type": "HEARTBEAT"}`+"\n")
+			fmt.Fprint(w, `{"type": "PRICE", "instrument": "EUR_USD", "bids": [{"price": "1.1100"}], "asks": [{"price": "1.1102"}]}`+"\n")
+			w.(http.Flusher).Flush()
+			<-r.Context().Done()
+		default:
+			t.Error("Got ", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, "token", server.Client())
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	livePL := NewLivePL(client, "001")
+	// The default interval is used instead
+	livePL.RefreshInterval = 0
+	done := make(chan error, 1)
+	go func() { done <- livePL.Run(ctx) }()
+
+	var updates []LivePLUpdate
+	for len(updates) < 2 {
+		select {
+		case update := <-livePL.Updates():
+			updates = append(updates, update)
+		case err := <-done:
+			t.Fatal(err)
+		}
+	}
+	cancel()
+	<-done
+
+	// The long Trade is valued at the bid, the short Trade at the ask
+	snapshot := updates[0]
+	if len(snapshot.Trades) != 2 || snapshot.Trades[0].UnrealizedPL.String() != "0.05" || snapshot.Trades[1].UnrealizedPL.String() != "0.095" {
+		t.Error("Got ", snapshot)
+	}
+	streamed := updates[1]
+	if streamed.Trades[0].UnrealizedPL.String() != "0.5" || streamed.Trades[1].UnrealizedPL.String() != "-0.156" {
+		t.Error("Got ", streamed)
+	}
+	if streamed.Position.LongUnrealizedPL.String() != "0.5" || streamed.Position.NetUnrealizedPL.String() != "0.344" {
+		t.Error("Got ", streamed.Position)
+	}
+}
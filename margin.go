@@ -0,0 +1,224 @@
+package oanda_sdk
+
+import (
+	"fmt"
+	"github.com/shopspring/decimal"
+	"sort"
+	"strings"
+)
+
+// MarginCalculator computes the price-dependent state of Trades, Positions and Accounts locally, following the
+// formulas OANDA uses. It serves what-if analysis of hypothetical Trades before they are placed, and checking the
+// state reported by OANDA.
+//
+// Trades are valued at the price they would be closed at: the bid for long Trades and the ask for short Trades, or
+// the closeout bid and ask for the margin closeout values. Amounts in the quote currency of an instrument are converted
+// into the home currency with the home conversions of the quote currency.
+type MarginCalculator struct {
+	// The mode the margin of the long and short sides of a Position are aggregated with.
+	Mode PositionAggregationMode
+
+	// The margin rate of the Account. When set, the margin rate used for an instrument is the higher of the Account's
+	// and the Instrument's margin rate.
+	AccountMarginRate *decimal.Decimal
+
+	instruments map[string]Instrument
+	prices      map[string]ClientPrice
+	conversions map[Currency]HomeConversions
+}
+
+// NewMarginCalculator creates a MarginCalculator with the Instruments, their current prices and home conversions,
+// e.g. as provided by [Client.GetAccountInstruments] and [Client.GetAccountPricing].
+func NewMarginCalculator(instruments []Instrument, prices []ClientPrice, homeConversions []HomeConversions, mode PositionAggregationMode) *MarginCalculator {
+	mc := &MarginCalculator{
+		Mode:        mode,
+		instruments: make(map[string]Instrument, len(instruments)),
+		prices:      make(map[string]ClientPrice, len(prices)),
+		conversions: make(map[Currency]HomeConversions, len(homeConversions)),
+	}
+	for _, instrument := range instruments {
+		mc.instruments[instrument.Name] = instrument
+	}
+	mc.UpdatePrices(prices, homeConversions)
+	return mc
+}
+
+// UpdatePrices replaces the prices and home conversions known to the MarginCalculator
+func (mc *MarginCalculator) UpdatePrices(prices []ClientPrice, homeConversions []HomeConversions) {
+	for _, price := range prices {
+		mc.prices[price.Instrument] = price
+	}
+	for _, conversion := range homeConversions {
+		mc.conversions[conversion.Currency] = conversion
+	}
+}
+
+// tradeValues are the amounts of a single Trade in the home currency
+type tradeValues struct {
+	unrealizedPL          decimal.Decimal
+	positionValue         decimal.Decimal
+	marginUsed            decimal.Decimal
+	closeoutUnrealizedPL  decimal.Decimal
+	closeoutPositionValue decimal.Decimal
+	closeoutMarginUsed    decimal.Decimal
+}
+
+func (mc *MarginCalculator) tradeValues(instrumentName string, units, openPrice decimal.Decimal) (tradeValues, error) {
+	instrument, ok := mc.instruments[instrumentName]
+	if !ok {
+		return tradeValues{}, fmt.Errorf("unknown instrument %s", instrumentName)
+	}
+	price, ok := mc.prices[instrumentName]
+	if !ok {
+		return tradeValues{}, fmt.Errorf("no price for %s", instrumentName)
+	}
+	_, quote, ok := strings.Cut(instrumentName, "_")
+	if !ok {
+		return tradeValues{}, fmt.Errorf("cannot determine the quote currency of %s", instrumentName)
+	}
+	conversion, ok := mc.conversions[Currency(quote)]
+	if !ok {
+		return tradeValues{}, fmt.Errorf("no home conversion for %s", quote)
+	}
+	closePrice, ok := price.ClosingPrice(units)
+	closeoutPrice := price.CloseoutBid
+	if units.IsNegative() {
+		closeoutPrice = price.CloseoutAsk
+	}
+	if !ok {
+		closePrice = closeoutPrice
+	}
+	marginRate := instrument.MarginRate
+	if mc.AccountMarginRate != nil && mc.AccountMarginRate.GreaterThan(marginRate) {
+		marginRate = *mc.AccountMarginRate
+	}
+	values := tradeValues{
+		unrealizedPL:          conversion.ConvertPL(units.Mul(closePrice.Sub(openPrice))),
+		positionValue:         units.Abs().Mul(closePrice).Mul(conversion.PositionValue),
+		closeoutUnrealizedPL:  conversion.ConvertPL(units.Mul(closeoutPrice.Sub(openPrice))),
+		closeoutPositionValue: units.Abs().Mul(closeoutPrice).Mul(conversion.PositionValue),
+	}
+	values.marginUsed = values.positionValue.Mul(marginRate)
+	values.closeoutMarginUsed = values.closeoutPositionValue.Mul(marginRate)
+	return values, nil
+}
+
+// TradeState calculates the price-dependent state of a Trade, which may be a hypothetical one. Only the Id,
+// Instrument, Price and CurrentUnits of the Trade are used.
+func (mc *MarginCalculator) TradeState(trade Trade) (CalculatedTradeState, error) {
+	values, err := mc.tradeValues(trade.Instrument, trade.CurrentUnits, trade.Price)
+	if err != nil {
+		return CalculatedTradeState{}, err
+	}
+	return CalculatedTradeState{ID: trade.Id, UnrealizedPL: values.unrealizedPL, MarginUsed: values.marginUsed}, nil
+}
+
+// MarginRequired calculates the margin a new Trade of the units would use at the current price
+func (mc *MarginCalculator) MarginRequired(instrument string, units decimal.Decimal) (decimal.Decimal, error) {
+	price, ok := mc.prices[instrument]
+	if !ok {
+		return decimal.Zero, fmt.Errorf("no price for %s", instrument)
+	}
+	openPrice, ok := price.BestAsk()
+	if units.IsNegative() {
+		openPrice, ok = price.BestBid()
+	}
+	if !ok {
+		return decimal.Zero, fmt.Errorf("no liquidity for %s", instrument)
+	}
+	values, err := mc.tradeValues(instrument, units, openPrice)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return values.marginUsed, nil
+}
+
+// AccountState calculates the price-dependent state of an Account with the given balance and open Trades, which may
+// include hypothetical ones. Only the Id, Instrument, Price and CurrentUnits of the Trades are used. The margin of
+// each Position is aggregated from its long and short Trades according to the Mode of the MarginCalculator.
+func (mc *MarginCalculator) AccountState(balance decimal.Decimal, trades []Trade) (*AccountChangesState, error) {
+	type sides struct {
+		long, short tradeValues
+		state       CalculatedPositionState
+	}
+	positions := make(map[string]*sides)
+	state := &AccountChangesState{}
+	var unrealizedPL, positionValue, closeoutUnrealizedPL, closeoutPositionValue decimal.Decimal
+	for _, trade := range trades {
+		values, err := mc.tradeValues(trade.Instrument, trade.CurrentUnits, trade.Price)
+		if err != nil {
+			return nil, err
+		}
+		state.Trades = append(state.Trades, CalculatedTradeState{ID: trade.Id, UnrealizedPL: values.unrealizedPL, MarginUsed: values.marginUsed})
+		unrealizedPL = unrealizedPL.Add(values.unrealizedPL)
+		positionValue = positionValue.Add(values.positionValue)
+		closeoutUnrealizedPL = closeoutUnrealizedPL.Add(values.closeoutUnrealizedPL)
+		closeoutPositionValue = closeoutPositionValue.Add(values.closeoutPositionValue)
+
+		position, ok := positions[trade.Instrument]
+		if !ok {
+			position = &sides{state: CalculatedPositionState{Instrument: trade.Instrument}}
+			positions[trade.Instrument] = position
+		}
+		side := &position.long
+		if trade.CurrentUnits.IsNegative() {
+			side = &position.short
+			position.state.ShortUnrealizedPL = position.state.ShortUnrealizedPL.Add(values.unrealizedPL)
+		} else {
+			position.state.LongUnrealizedPL = position.state.LongUnrealizedPL.Add(values.unrealizedPL)
+		}
+		side.marginUsed = side.marginUsed.Add(values.marginUsed)
+		side.closeoutMarginUsed = side.closeoutMarginUsed.Add(values.closeoutMarginUsed)
+	}
+
+	var marginUsed, closeoutMarginUsed decimal.Decimal
+	for _, position := range positions {
+		position.state.NetUnrealizedPL = position.state.LongUnrealizedPL.Add(position.state.ShortUnrealizedPL)
+		position.state.MarginUsed = mc.aggregate(position.long.marginUsed, position.short.marginUsed)
+		marginUsed = marginUsed.Add(position.state.MarginUsed)
+		closeoutMarginUsed = closeoutMarginUsed.Add(mc.aggregate(position.long.closeoutMarginUsed, position.short.closeoutMarginUsed))
+		state.Positions = append(state.Positions, position.state)
+	}
+	sort.Slice(state.Positions, func(i, j int) bool {
+		return state.Positions[i].Instrument < state.Positions[j].Instrument
+	})
+
+	nav := balance.Add(unrealizedPL)
+	closeoutNAV := balance.Add(closeoutUnrealizedPL)
+	marginAvailable := decimal.Max(nav.Sub(marginUsed), decimal.Zero)
+	closeoutPercent, callPercent := decimal.Zero, decimal.Zero
+	if closeoutNAV.IsPositive() {
+		// The Account is closed out when its NAV falls under half of the margin used
+		closeoutPercent = closeoutMarginUsed.Div(closeoutNAV.Mul(decimal.NewFromInt(2)))
+	}
+	if nav.IsPositive() {
+		callPercent = marginUsed.Div(nav)
+	}
+	state.Balance = &balance
+	state.UnrealizedPL = &unrealizedPL
+	state.NAV = &nav
+	state.MarginUsed = &marginUsed
+	state.MarginAvailable = &marginAvailable
+	state.PositionValue = &positionValue
+	state.MarginCloseoutUnrealizedPL = &closeoutUnrealizedPL
+	state.MarginCloseoutNAV = &closeoutNAV
+	state.MarginCloseoutMarginUsed = &closeoutMarginUsed
+	state.MarginCloseoutPercent = &closeoutPercent
+	state.MarginCloseoutPositionValue = &closeoutPositionValue
+	state.MarginCallMarginUsed = &marginUsed
+	state.MarginCallPercent = &callPercent
+	return state, nil
+}
+
+// aggregate combines the values of the long and short side of a Position according to the Mode
+func (mc *MarginCalculator) aggregate(long, short decimal.Decimal) decimal.Decimal {
+	switch mc.Mode {
+	case MaximalSide:
+		return decimal.Max(long, short)
+	case NetSum:
+		// The sides are valued at different prices, netting their values approximates netting their units
+		return long.Sub(short).Abs()
+	default:
+		return long.Add(short)
+	}
+}
@@ -0,0 +1,73 @@
+package oanda_sdk
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func newTestMarginCalculator(mode PositionAggregationMode) *MarginCalculator {
+	d := decimal.RequireFromString
+	return NewMarginCalculator(
+		[]Instrument{{Name: "EUR_USD", MarginRate: d("0.02")}},
+		[]ClientPrice{{
+			Instrument:  "EUR_USD",
+			Bids:        []PriceBucket{{Price: d("1.1000")}},
+			Asks:        []PriceBucket{{Price: d("1.1002")}},
+			CloseoutBid: d("1.0999"),
+			CloseoutAsk: d("1.1003"),
+		}},
+		[]HomeConversions{{Currency: "USD", AccountGain: d("1"), AccountLoss: d("1"), PositionValue: d("1")}},
+		mode,
+	)
+}
+
+func TestMarginCalculatorAccountState(t *testing.T) {
+	d := decimal.RequireFromString
+	calculator := newTestMarginCalculator(AbsoluteSum)
+	state, err := calculator.AccountState(d("1000"), []Trade{
+		{Id: "1", Instrument: "EUR_USD", Price: d("1.0950"), CurrentUnits: d("10000")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, pair := range map[string][2]decimal.Decimal{
+		"unrealizedPL":          {*state.UnrealizedPL, d("50")},
+		"NAV":                   {*state.NAV, d("1050")},
+		"positionValue":         {*state.PositionValue, d("11000")},
+		"marginUsed":            {*state.MarginUsed, d("220")},
+		"marginAvailable":       {*state.MarginAvailable, d("830")},
+		"marginCloseoutNAV":     {*state.MarginCloseoutNAV, d("1049")},
+		"marginCloseoutPercent": {state.MarginCloseoutPercent.Round(5), d("0.10485")},
+		"marginCallPercent":     {state.MarginCallPercent.Round(5), d("0.20952")},
+	} {
+		if !pair[0].Equal(pair[1]) {
+			t.Error("Got ", name, pair[0])
+		}
+	}
+	if len(state.Trades) != 1 || !state.Trades[0].MarginUsed.Equal(d("220")) {
+		t.Error("Got ", state.Trades)
+	}
+}
+
+func TestMarginCalculatorAggregatesSides(t *testing.T) {
+	d := decimal.RequireFromString
+	trades := []Trade{
+		{Id: "1", Instrument: "EUR_USD", Price: d("1.1000"), CurrentUnits: d("10000")},
+		{Id: "2", Instrument: "EUR_USD", Price: d("1.1000"), CurrentUnits: d("-5000")},
+	}
+	// The long side uses 220 of margin, the short side 110.02
+	for mode, expected := range map[PositionAggregationMode]string{AbsoluteSum: "330.02", MaximalSide: "220", NetSum: "109.98"} {
+		state, err := newTestMarginCalculator(mode).AccountState(d("1000"), trades)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !state.MarginUsed.Equal(d(expected)) || !state.Positions[0].MarginUsed.Equal(d(expected)) {
+			t.Error("Got ", mode, state.MarginUsed)
+		}
+	}
+	required, err := newTestMarginCalculator(AbsoluteSum).MarginRequired("EUR_USD", d("-1000"))
+	if err != nil || !required.Equal(d("22.004")) {
+		t.Error("Got ", required, err)
+	}
+}
@@ -1,7 +1,10 @@
 package oanda_sdk
 
 import (
+	"encoding/json"
+	"fmt"
 	"github.com/shopspring/decimal"
+	"strings"
 	"time"
 )
 
@@ -24,6 +27,69 @@ type Order interface {
 	GetType() OrderType
 }
 
+// orderDecoders maps each OrderType onto a function decoding its concrete representation
+var orderDecoders = map[OrderType]func([]byte) (Order, error){
+	Market:             decodeOrder[MarketOrder],
+	FixedPrice:         decodeOrder[FixedPriceOrder],
+	Limit:              decodeOrder[LimitOrder],
+	Stop:               decodeOrder[StopOrder],
+	MarketIfTouched:    decodeOrder[MarketIfTouchedOrder],
+	TakeProfit:         decodeOrder[TakeProfitOrder],
+	StopLoss:           decodeOrder[StopLossOrder],
+	GuaranteedStopLoss: decodeOrder[GuaranteedStopLossOrder],
+	TrailingStopLoss:   decodeOrder[TrailingStopLossOrder],
+}
+
+func decodeOrder[T Order](data []byte) (Order, error) {
+	var order T
+	err := json.Unmarshal(data, &order)
+	if err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// UnmarshalOrder decodes a JSON encoded Order into its concrete type based on its type field. A JSON null results in
+// a nil Order.
+func UnmarshalOrder(data []byte) (Order, error) {
+	if len(data) == 0 || string(data) == "null" {
+		return nil, nil
+	}
+	var base struct {
+		Type OrderType `json:"type"`
+	}
+	err := json.Unmarshal(data, &base)
+	if err != nil {
+		return nil, err
+	}
+	decoder, ok := orderDecoders[base.Type]
+	if !ok {
+		return nil, fmt.Errorf("unknown order type %q", base.Type)
+	}
+	return decoder(data)
+}
+
+// unmarshalOrders decodes a JSON array of Orders into their concrete types
+func unmarshalOrders(data []byte) ([]Order, error) {
+	if len(data) == 0 || string(data) == "null" {
+		return nil, nil
+	}
+	var raw []json.RawMessage
+	err := json.Unmarshal(data, &raw)
+	if err != nil {
+		return nil, err
+	}
+	orders := make([]Order, 0, len(raw))
+	for _, item := range raw {
+		order, err := UnmarshalOrder(item)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, order)
+	}
+	return orders, nil
+}
+
 // MarketOrder is an order that is filled immediately upon creation using the current market price
 type MarketOrder struct {
 	// The Order’s identifier, unique within the Order’s Account.
@@ -1755,8 +1821,27 @@ type OrderIdentifier struct {
 }
 
 // OrderSpecifier is a specification of an Order as referred to by clients
+// Format: Either the Order’s OANDA-assigned OrderID or the Order’s client-provided ClientID prefixed by the “@” symbol
+// Example: @my_order_id
 type OrderSpecifier string
 
+// OrderByID specifies an Order by its OANDA-assigned OrderID
+func OrderByID(orderID OrderID) OrderSpecifier {
+	return OrderSpecifier(orderID)
+}
+
+// OrderByClientID specifies an Order by the ClientID the client has assigned to it
+func OrderByClientID(clientID ClientID) OrderSpecifier {
+	return OrderSpecifier("@" + clientID)
+}
+
+// ClientID returns the ClientID of an Order specified by its ClientID. The second return value is false when the
+// Order is specified by its OrderID.
+func (os OrderSpecifier) ClientID() (ClientID, bool) {
+	clientID, ok := strings.CutPrefix(string(os), "@")
+	return ClientID(clientID), ok
+}
+
 type TimeInForce string
 
 const (
@@ -0,0 +1,368 @@
+package oanda_sdk
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// OrderEventType classifies the Transactions reported by the OrderTracker for an Order
+type OrderEventType string
+
+const (
+	// OrderEventCreated is reported when the Order is created and becomes PENDING
+	OrderEventCreated = OrderEventType("CREATED")
+
+	// OrderEventTriggered is reported when a pending Order (e.g. a limit, stop or stop loss Order) is triggered by the
+	// price. OANDA reports the trigger in the OrderFillTransaction of the fill it leads to, so the event is followed by
+	// the FILLED event of the same Transaction.
+	OrderEventTriggered = OrderEventType("TRIGGERED")
+
+	// OrderEventFilled is reported when the Order is filled
+	OrderEventFilled = OrderEventType("FILLED")
+
+	// OrderEventCancelled is reported when the Order is cancelled
+	OrderEventCancelled = OrderEventType("CANCELLED")
+
+	// OrderEventRejected is reported when the creation of the Order is rejected. Rejected Orders never get an OrderID,
+	// so they can only be matched by their client ID.
+	OrderEventRejected = OrderEventType("REJECTED")
+
+	// OrderEventCancelRejected is reported when the cancellation of the Order is rejected. The Order stays PENDING.
+	OrderEventCancelRejected = OrderEventType("CANCEL_REJECTED")
+)
+
+// OrderEvent is a single step in the lifecycle of an Order
+type OrderEvent struct {
+	// The type of the event.
+	Type OrderEventType
+
+	// The ID of the Order. Not set for Orders whose creation was rejected.
+	OrderID OrderID
+
+	// The client ID of the Order, only set if the client has assigned one.
+	ClientOrderID ClientID
+
+	// The state of the Order after the event. Not set for Orders whose creation was rejected.
+	State OrderState
+
+	// The Transaction that caused the event.
+	Transaction Transaction
+}
+
+// Final reports whether the event ends the lifecycle of the Order
+func (oe OrderEvent) Final() bool {
+	return oe.Type == OrderEventFilled || oe.Type == OrderEventCancelled || oe.Type == OrderEventRejected
+}
+
+// ErrOrderNotTracked is returned by the OrderTracker for an Order it has neither seen nor been asked to track
+var ErrOrderNotTracked = errors.New("order not tracked")
+
+// OrderTracker follows the lifecycle of Orders through the Transactions of their Account. It matches the Transactions
+// to Orders by both their OrderID and client ID, so an Order can be tracked or awaited using either form of the
+// OrderSpecifier before it is even created.
+//
+// The OrderTracker remembers every Order it has seen; use Forget to release Orders which are no longer of interest.
+type OrderTracker struct {
+	mu         sync.Mutex
+	byOrderID  map[OrderID]*trackedOrder
+	byClientID map[ClientID]*trackedOrder
+}
+
+type trackedOrder struct {
+	orderID   OrderID
+	clientID  ClientID
+	events    []OrderEvent
+	final     *OrderEvent
+	done      chan struct{}
+	callbacks []func(OrderEvent)
+	merged    []*trackedOrder
+}
+
+// NewOrderTracker creates an empty OrderTracker
+func NewOrderTracker() *OrderTracker {
+	return &OrderTracker{
+		byOrderID:  make(map[OrderID]*trackedOrder),
+		byClientID: make(map[ClientID]*trackedOrder),
+	}
+}
+
+// Run feeds the Transactions from the channel (typically the one returned by [Client.GetAccountTransactionsStream])
+// into the OrderTracker until the context is done or the channel is closed
+func (ot *OrderTracker) Run(ctx context.Context, transactions <-chan Transaction) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case transaction, ok := <-transactions:
+			if !ok {
+				return errors.New("transaction stream closed")
+			}
+			ot.HandleTransaction(transaction)
+		}
+	}
+}
+
+// HandleCreateOrderResponse applies the Transactions of a CreateOrder response. The same Transactions are reported
+// through the Transaction stream too, each of them is applied only once.
+func (ot *OrderTracker) HandleCreateOrderResponse(response *CreateOrderResponse) {
+	ot.HandleTransaction(response.OrderCreateTransaction)
+	if response.OrderFillTransaction != nil {
+		ot.HandleTransaction(*response.OrderFillTransaction)
+	}
+	if response.OrderCancelTransaction != nil {
+		ot.HandleTransaction(*response.OrderCancelTransaction)
+	}
+}
+
+// HandleTransaction applies a single Transaction. Transactions not related to the lifecycle of an Order are ignored.
+func (ot *OrderTracker) HandleTransaction(transaction Transaction) {
+	event, ok := orderEventOf(transaction)
+	if !ok {
+		return
+	}
+	events := []OrderEvent{event}
+	if fill, ok := transaction.(OrderFillTransaction); ok && triggeredFillReasons[fill.Reason] {
+		triggered := event
+		triggered.Type, triggered.State = OrderEventTriggered, Triggered
+		events = []OrderEvent{triggered, event}
+	}
+	ot.mu.Lock()
+	order := ot.lookup(event.OrderID, event.ClientOrderID)
+	for _, seen := range order.events {
+		if seen.Transaction.GetId() == transaction.GetId() {
+			ot.mu.Unlock()
+			return
+		}
+	}
+	if order.final != nil {
+		ot.mu.Unlock()
+		return
+	}
+	order.events = append(order.events, events...)
+	callbacks := append([]func(OrderEvent){}, order.callbacks...)
+	if event.Final() {
+		order.finish(event)
+	}
+	ot.mu.Unlock()
+	for _, event := range events {
+		for _, callback := range callbacks {
+			callback(event)
+		}
+	}
+}
+
+// Track starts tracking the specified Order before any of its Transactions is seen, e.g. to register callbacks with
+// On before the Order is created
+func (ot *OrderTracker) Track(orderSpecifier OrderSpecifier) {
+	ot.mu.Lock()
+	defer ot.mu.Unlock()
+	ot.lookupSpecifier(orderSpecifier)
+}
+
+// On registers a callback invoked for every further event of the specified Order. Events already observed for the
+// Order are replayed to the callback first. ErrOrderNotTracked is returned for an Order neither seen nor tracked.
+func (ot *OrderTracker) On(orderSpecifier OrderSpecifier, callback func(OrderEvent)) error {
+	ot.mu.Lock()
+	order, ok := ot.find(orderSpecifier)
+	if !ok {
+		ot.mu.Unlock()
+		return ErrOrderNotTracked
+	}
+	order.callbacks = append(order.callbacks, callback)
+	events := append([]OrderEvent{}, order.events...)
+	ot.mu.Unlock()
+	for _, event := range events {
+		callback(event)
+	}
+	return nil
+}
+
+// Await blocks until the specified Order is filled, cancelled or rejected and returns the final event
+func (ot *OrderTracker) Await(ctx context.Context, orderSpecifier OrderSpecifier) (OrderEvent, error) {
+	ot.mu.Lock()
+	order := ot.lookupSpecifier(orderSpecifier)
+	ot.mu.Unlock()
+	select {
+	case <-order.done:
+		ot.mu.Lock()
+		defer ot.mu.Unlock()
+		return *order.final, nil
+	case <-ctx.Done():
+		return OrderEvent{}, ctx.Err()
+	}
+}
+
+// Events returns the events observed so far for the specified Order. ErrOrderNotTracked is returned for an Order
+// neither seen nor tracked.
+func (ot *OrderTracker) Events(orderSpecifier OrderSpecifier) ([]OrderEvent, error) {
+	ot.mu.Lock()
+	defer ot.mu.Unlock()
+	order, ok := ot.find(orderSpecifier)
+	if !ok {
+		return nil, ErrOrderNotTracked
+	}
+	return append([]OrderEvent{}, order.events...), nil
+}
+
+// Forget releases the specified Order from the OrderTracker. ErrOrderNotTracked is returned for an Order neither seen
+// nor tracked.
+func (ot *OrderTracker) Forget(orderSpecifier OrderSpecifier) error {
+	ot.mu.Lock()
+	defer ot.mu.Unlock()
+	order, ok := ot.find(orderSpecifier)
+	if !ok {
+		return ErrOrderNotTracked
+	}
+	delete(ot.byOrderID, order.orderID)
+	delete(ot.byClientID, order.clientID)
+	return nil
+}
+
+// find finds the Order by the specifier without creating it
+func (ot *OrderTracker) find(orderSpecifier OrderSpecifier) (*trackedOrder, bool) {
+	var order *trackedOrder
+	if clientID, ok := orderSpecifier.ClientID(); ok {
+		order = ot.byClientID[clientID]
+	} else {
+		order = ot.byOrderID[OrderID(orderSpecifier)]
+	}
+	return order, order != nil
+}
+
+func (ot *OrderTracker) lookupSpecifier(orderSpecifier OrderSpecifier) *trackedOrder {
+	if clientID, ok := orderSpecifier.ClientID(); ok {
+		return ot.lookup("", clientID)
+	}
+	return ot.lookup(OrderID(orderSpecifier), "")
+}
+
+// lookup finds the Order by either of its identifiers, creating it when it is not known yet. When the Order was known
+// under both identifiers separately, the two records are merged.
+func (ot *OrderTracker) lookup(orderID OrderID, clientID ClientID) *trackedOrder {
+	var byOrderID, byClientID *trackedOrder
+	if orderID != "" {
+		byOrderID = ot.byOrderID[orderID]
+	}
+	if clientID != "" {
+		byClientID = ot.byClientID[clientID]
+	}
+	order := byOrderID
+	switch {
+	case order == nil && byClientID == nil:
+		order = &trackedOrder{done: make(chan struct{})}
+	case order == nil:
+		order = byClientID
+	case byClientID != nil && byClientID != order:
+		order.merge(byClientID)
+	}
+	if orderID != "" {
+		order.orderID = orderID
+		ot.byOrderID[orderID] = order
+	}
+	if clientID != "" {
+		order.clientID = clientID
+		ot.byClientID[clientID] = order
+	}
+	return order
+}
+
+func (to *trackedOrder) merge(other *trackedOrder) {
+	to.events = append(to.events, other.events...)
+	to.callbacks = append(to.callbacks, other.callbacks...)
+	to.merged = append(to.merged, other)
+	if to.final == nil && other.final != nil {
+		to.finish(*other.final)
+	}
+}
+
+func (to *trackedOrder) finish(event OrderEvent) {
+	if to.final == nil {
+		to.final = &event
+		close(to.done)
+	}
+	for _, merged := range to.merged {
+		merged.finish(event)
+	}
+}
+
+// triggeredFillReasons are the reasons of the fills of pending Orders triggered by the price
+var triggeredFillReasons = map[OrderFillReason]bool{
+	OrderFillReasonLimitOrder:              true,
+	OrderFillReasonStopOrder:               true,
+	OrderFillReasonMarketIfTouchedOrder:    true,
+	OrderFillReasonTakeProfitOrder:         true,
+	OrderFillReasonStopLossOrder:           true,
+	OrderFillReasonGuaranteedStopLossOrder: true,
+	OrderFillReasonTrailingStopLossOrder:   true,
+}
+
+// orderEventOf classifies a Transaction into an OrderEvent
+func orderEventOf(transaction Transaction) (OrderEvent, bool) {
+	event := OrderEvent{Transaction: transaction}
+	var clientExtensions *ClientExtensions
+	switch tx := transaction.(type) {
+	case OrderFillTransaction:
+		event.Type, event.State, event.OrderID = OrderEventFilled, Filled, tx.OrderID
+		if tx.ClientOrderID != nil {
+			event.ClientOrderID = *tx.ClientOrderID
+		}
+		return event, true
+	case OrderCancelTransaction:
+		event.Type, event.State, event.OrderID, event.ClientOrderID = OrderEventCancelled, Cancelled, tx.OrderID, tx.ClientOrderID
+		return event, true
+	case OrderCancelRejectTransaction:
+		event.Type, event.State, event.OrderID, event.ClientOrderID = OrderEventCancelRejected, Pending, tx.OrderID, tx.ClientOrderID
+		return event, true
+	case MarketOrderTransaction:
+		clientExtensions = tx.ClientExtensions
+	case FixedPriceOrderTransaction:
+		clientExtensions = tx.ClientExtensions
+	case LimitOrderTransaction:
+		clientExtensions = tx.ClientExtensions
+	case StopOrderTransaction:
+		clientExtensions = tx.ClientExtensions
+	case MarketIfTouchedOrderTransaction:
+		clientExtensions = tx.ClientExtensions
+	case TakeProfitOrderTransaction:
+		clientExtensions = tx.ClientExtensions
+	case StopLossOrderTransaction:
+		clientExtensions = tx.ClientExtensions
+	case GuaranteedStopLossOrderTransaction:
+		clientExtensions = tx.ClientExtensions
+	case TrailingStopLossOrderTransaction:
+		clientExtensions = tx.ClientExtensions
+	case MarketOrderRejectTransaction:
+		return rejectedOrderEvent(event, tx.ClientExtensions)
+	case LimitOrderRejectTransaction:
+		return rejectedOrderEvent(event, tx.ClientExtensions)
+	case StopOrderRejectTransaction:
+		return rejectedOrderEvent(event, tx.ClientExtensions)
+	case MarketIfTouchedOrderRejectTransaction:
+		return rejectedOrderEvent(event, tx.ClientExtensions)
+	case TakeProfitOrderRejectTransaction:
+		return rejectedOrderEvent(event, tx.ClientExtensions)
+	case StopLossOrderRejectTransaction:
+		return rejectedOrderEvent(event, tx.ClientExtensions)
+	case GuaranteedStopLossOrderRejectTransaction:
+		return rejectedOrderEvent(event, tx.ClientExtensions)
+	case TrailingStopLossOrderRejectTransaction:
+		return rejectedOrderEvent(event, tx.ClientExtensions)
+	default:
+		return event, false
+	}
+	// The Order created by an Order Transaction is identified by the ID of the Transaction
+	event.Type, event.State, event.OrderID = OrderEventCreated, Pending, OrderID(transaction.GetId())
+	if clientExtensions != nil {
+		event.ClientOrderID = clientExtensions.Id
+	}
+	return event, true
+}
+
+func rejectedOrderEvent(event OrderEvent, clientExtensions *ClientExtensions) (OrderEvent, bool) {
+	if clientExtensions == nil || clientExtensions.Id == "" {
+		return event, false
+	}
+	event.Type, event.ClientOrderID = OrderEventRejected, clientExtensions.Id
+	return event, true
+}
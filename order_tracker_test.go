@@ -0,0 +1,115 @@
+package oanda_sdk
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestOrderTrackerAwaitByClientID(t *testing.T) {
+	tracker := NewOrderTracker()
+	var events []OrderEventType
+	if err := tracker.On("@my_order", func(OrderEvent) {}); err != ErrOrderNotTracked {
+		t.Error("Got ", err)
+	}
+	tracker.Track("@my_order")
+	err := tracker.On("@my_order", func(event OrderEvent) {
+		events = append(events, event.Type)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientID := ClientID("my_order")
+	tracker.HandleTransaction(LimitOrderTransaction{
+		TransactionBase:  TransactionBase{Id: "100"},
+		ClientExtensions: &ClientExtensions{Id: clientID},
+	})
+	fill := OrderFillTransaction{
+		TransactionBase: TransactionBase{Id: "101"},
+		OrderID:         "100",
+		ClientOrderID:   &clientID,
+		Reason:          OrderFillReasonLimitOrder,
+	}
+	tracker.HandleTransaction(fill)
+	tracker.HandleTransaction(fill)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	event, err := tracker.Await(ctx, "100")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if event.Type != OrderEventFilled || event.State != Filled || event.ClientOrderID != clientID {
+		t.Error("Got ", event)
+	}
+	// The limit Order is triggered and filled by the same Transaction
+	if fmt.Sprint(events) != "[CREATED TRIGGERED FILLED]" {
+		t.Error("Got ", events)
+	}
+	if observed, err := tracker.Events("100"); err != nil || len(observed) != 3 || observed[1].State != Triggered {
+		t.Error("Got ", observed, err)
+	}
+	if err := tracker.Forget("@my_order"); err != nil {
+		t.Error("Got ", err)
+	}
+	// The Order is no longer known by either of its identifiers, and unknown Orders are not created by the lookups
+	for _, specifier := range []OrderSpecifier{"100", "@my_order", "999"} {
+		if _, err := tracker.Events(specifier); err != ErrOrderNotTracked {
+			t.Error("Got ", specifier, err)
+		}
+		if err := tracker.Forget(specifier); err != ErrOrderNotTracked {
+			t.Error("Got ", specifier, err)
+		}
+	}
+}
+
+func TestOrderTrackerMergesOrderAndClientID(t *testing.T) {
+	tracker := NewOrderTracker()
+	byOrderID := make(chan OrderEvent, 1)
+	go func() {
+		event, _ := tracker.Await(context.Background(), "200")
+		byOrderID <- event
+	}()
+	byClientID := make(chan OrderEvent, 1)
+	go func() {
+		event, _ := tracker.Await(context.Background(), "@other_order")
+		byClientID <- event
+	}()
+	time.Sleep(10 * time.Millisecond)
+	tracker.HandleTransaction(OrderCancelTransaction{
+		TransactionBase: TransactionBase{Id: "201"},
+		OrderID:         "200",
+		ClientOrderID:   "other_order",
+		Reason:          OrderCancelReasonClientRequest,
+	})
+	for _, events := range []chan OrderEvent{byOrderID, byClientID} {
+		select {
+		case event := <-events:
+			if event.Type != OrderEventCancelled {
+				t.Error("Got ", event)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("Await did not return")
+		}
+	}
+}
+
+func TestOrderTrackerRejectedOrder(t *testing.T) {
+	tracker := NewOrderTracker()
+	tracker.HandleTransaction(MarketOrderRejectTransaction{
+		MarketOrderTransaction: MarketOrderTransaction{
+			TransactionBase:  TransactionBase{Id: "300"},
+			ClientExtensions: &ClientExtensions{Id: "rejected_order"},
+		},
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	event, err := tracker.Await(ctx, "@rejected_order")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if event.Type != OrderEventRejected || event.OrderID != "" {
+		t.Error("Got ", event)
+	}
+}
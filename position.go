@@ -1,6 +1,9 @@
 package oanda_sdk
 
-import "github.com/shopspring/decimal"
+import (
+	"fmt"
+	"github.com/shopspring/decimal"
+)
 
 // Position specifies a Position within an Account.
 type Position struct {
@@ -70,6 +73,43 @@ type PositionSide struct {
 	GuaranteedExecutionFees decimal.Decimal `json:"guaranteedExecutionFees"`
 }
 
+// CloseUnits indicates how much of a side of a Position to close: all of it, none of it or a positive number of units
+type CloseUnits string
+
+const (
+	// CloseAll closes the whole side of the Position
+	CloseAll = CloseUnits("ALL")
+
+	// CloseNone leaves the side of the Position open
+	CloseNone = CloseUnits("NONE")
+)
+
+// CloseUnitsOf closes the given positive number of units of a side of a Position
+func CloseUnitsOf(units decimal.Decimal) CloseUnits {
+	return CloseUnits(units.String())
+}
+
+// Units returns the number of units to close. The second return value is false for ALL and NONE.
+func (cu CloseUnits) Units() (decimal.Decimal, bool) {
+	if cu == CloseAll || cu == CloseNone {
+		return decimal.Zero, false
+	}
+	units, err := decimal.NewFromString(string(cu))
+	return units, err == nil
+}
+
+// Validate checks that the CloseUnits are ALL, NONE or a positive number of units
+func (cu CloseUnits) Validate() error {
+	if cu == CloseAll || cu == CloseNone {
+		return nil
+	}
+	units, err := decimal.NewFromString(string(cu))
+	if err != nil || !units.IsPositive() {
+		return fmt.Errorf("invalid close units %q: must be ALL, NONE or a positive number", string(cu))
+	}
+	return nil
+}
+
 // CalculatedPositionState represents the dynamic (calculated) state of a Position
 type CalculatedPositionState struct {
 	// The Position’s Instrument.
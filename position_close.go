@@ -0,0 +1,110 @@
+package oanda_sdk
+
+import (
+	"errors"
+	"fmt"
+	"github.com/shopspring/decimal"
+)
+
+// ErrPositionNotClosed is returned by ClosePosition when the Position does not reflect the requested close
+var ErrPositionNotClosed = errors.New("position not closed as requested")
+
+// PositionCloseSummary describes the outcome of closing a Position
+type PositionCloseSummary struct {
+	// The instrument of the Position.
+	Instrument string
+
+	// The response to the close request.
+	Response *CloseAccountInstrumentPositionResponse
+
+	// The Transactions filling the MarketOrders which closed the long and short sides of the Position.
+	Fills []OrderFillTransaction
+
+	// The profit/loss realized by the fills, in the home currency.
+	RealizedPL decimal.Decimal
+
+	// The financing paid/collected by the fills, in the home currency.
+	Financing decimal.Decimal
+
+	// The commission charged by the fills, in the home currency.
+	Commission decimal.Decimal
+
+	// The guaranteed execution fees charged by the fills, in the home currency.
+	GuaranteedExecutionFee decimal.Decimal
+
+	// The Position after the close.
+	Position Position
+}
+
+// ClosePosition closes the sides of a Position as requested and verifies the outcome against the Position after the
+// close: a side closed with ALL must have no units left, a side closed with a number of units must be reduced by
+// exactly that many. When the verification fails, the summary is returned with an error wrapping
+// ErrPositionNotClosed.
+func (c *Client) ClosePosition(accountID AccountID, instrument string, request CloseAccountInstrumentPositionRequest) (*PositionCloseSummary, error) {
+	for _, units := range []CloseUnits{request.LongUnits, request.ShortUnits} {
+		if units == "" {
+			continue
+		}
+		if err := units.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	before, err := c.GetAccountInstrumentPosition(accountID, instrument)
+	if err != nil {
+		return nil, err
+	}
+	response, err := c.CloseAccountInstrumentPosition(accountID, instrument, request)
+	if err != nil {
+		return nil, err
+	}
+	summary := &PositionCloseSummary{Instrument: instrument, Response: response}
+	for _, fill := range []*OrderFillTransaction{response.LongOrderFillTransaction, response.ShortOrderFillTransaction} {
+		if fill == nil {
+			continue
+		}
+		summary.Fills = append(summary.Fills, *fill)
+		summary.RealizedPL = summary.RealizedPL.Add(fill.PL)
+		summary.Financing = summary.Financing.Add(fill.Financing)
+		summary.Commission = summary.Commission.Add(fill.Commission)
+		summary.GuaranteedExecutionFee = summary.GuaranteedExecutionFee.Add(fill.GuaranteedExecutionFee)
+	}
+	after, err := c.GetAccountInstrumentPosition(accountID, instrument)
+	if err != nil {
+		return summary, err
+	}
+	summary.Position = after.Position
+
+	var errs []error
+	if cancel := response.LongOrderCancelTransaction; cancel != nil {
+		errs = append(errs, fmt.Errorf("long close order cancelled: %s", cancel.Reason))
+	}
+	if cancel := response.ShortOrderCancelTransaction; cancel != nil {
+		errs = append(errs, fmt.Errorf("short close order cancelled: %s", cancel.Reason))
+	}
+	errs = append(errs,
+		verifyPositionSideClose("long", request.LongUnits, before.Position.Long, after.Position.Long),
+		verifyPositionSideClose("short", request.ShortUnits, before.Position.Short, after.Position.Short),
+	)
+	if err := errors.Join(errs...); err != nil {
+		return summary, fmt.Errorf("%w: %w", ErrPositionNotClosed, err)
+	}
+	return summary, nil
+}
+
+// verifyPositionSideClose checks that a side of a Position was closed as requested
+func verifyPositionSideClose(side string, units CloseUnits, before, after PositionSide) error {
+	switch units {
+	case CloseNone:
+		return nil
+	case "", CloseAll:
+		if !after.Units.IsZero() {
+			return fmt.Errorf("%s side still has %s units", side, after.Units)
+		}
+		return nil
+	}
+	closed, _ := units.Units()
+	if expected := before.Units.Abs().Sub(closed); !after.Units.Abs().Equal(expected) {
+		return fmt.Errorf("%s side has %s units, expected %s", side, after.Units.Abs(), expected)
+	}
+	return nil
+}
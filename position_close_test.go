@@ -0,0 +1,83 @@
+package oanda_sdk
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func newPositionCloseServer(t *testing.T, positions []string, body *string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v3/accounts/001/positions/EUR_USD":
+			fmt.Fprintf(w, `{"position": %s}`, positions[0])
+			positions = positions[1:]
+		case "/v3/accounts/001/positions/EUR_USD/close":
+			content, _ := io.ReadAll(r.Body)
+			*body = string(content)
+			fmt.Fprint(w, `{
+				"longOrderFillTransaction": {"id": "10", "type": "ORDER_FILL", "pl": "12.5", "financing": "-0.5", "commission": "0.1"},
+				"shortOrderFillTransaction": {"id": "11", "type": "ORDER_FILL", "pl": "-2.5", "financing": "0.25"}
+			}`)
+		default:
+			t.Error("Got ", r.URL.Path)
+		}
+	}))
+}
+
+func TestClosePositionSummarizesAndVerifiesClose(t *testing.T) {
+	var body string
+	server := newPositionCloseServer(t, []string{
+		`{"instrument": "EUR_USD", "long": {"units": "300"}, "short": {"units": "-100"}}`,
+		`{"instrument": "EUR_USD", "long": {"units": "200"}, "short": {"units": "0"}}`,
+	}, &body)
+	defer server.Close()
+	client := NewClient(server.URL, "token", server.Client())
+	summary, err := client.ClosePosition("001", "EUR_USD", CloseAccountInstrumentPositionRequest{
+		LongUnits:             CloseUnitsOf(decimal.RequireFromString("100")),
+		ShortUnits:            CloseAll,
+		ShortClientExtensions: &ClientExtensions{Tag: "exit"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var request map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(body), &request); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := request["longClientExtensions"]; ok || string(request["longUnits"]) != `"100"` || string(request["shortUnits"]) != `"ALL"` {
+		t.Error("Got ", body)
+	}
+	if _, ok := request["shortClientExtensions"]; !ok {
+		t.Error("Got ", body)
+	}
+	if len(summary.Fills) != 2 || summary.RealizedPL.String() != "10" || summary.Financing.String() != "-0.25" || summary.Commission.String() != "0.1" {
+		t.Error("Got ", summary)
+	}
+	if !summary.Position.Long.Units.Equal(decimal.RequireFromString("200")) {
+		t.Error("Got ", summary.Position)
+	}
+}
+
+func TestClosePositionReportsUnclosedSide(t *testing.T) {
+	var body string
+	server := newPositionCloseServer(t, []string{
+		`{"instrument": "EUR_USD", "long": {"units": "300"}, "short": {"units": "-100"}}`,
+		`{"instrument": "EUR_USD", "long": {"units": "300"}, "short": {"units": "0"}}`,
+	}, &body)
+	defer server.Close()
+	client := NewClient(server.URL, "token", server.Client())
+	summary, err := client.ClosePosition("001", "EUR_USD", CloseAccountInstrumentPositionRequest{})
+	if !errors.Is(err, ErrPositionNotClosed) || summary == nil {
+		t.Error("Got ", summary, err)
+	}
+	if _, err := client.ClosePosition("001", "EUR_USD", CloseAccountInstrumentPositionRequest{LongUnits: "-5"}); err == nil {
+		t.Error("Expected an error for negative close units")
+	}
+}
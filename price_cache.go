@@ -0,0 +1,339 @@
+package oanda_sdk
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultPricePollInterval is the default interval at which a PriceCache polls the Prices while the pricing stream
+	// is down
+	DefaultPricePollInterval = 5 * time.Second
+
+	// DefaultPriceStreamRetryInterval is the default time a PriceCache polls the Prices for before reopening the
+	// pricing stream
+	DefaultPriceStreamRetryInterval = 30 * time.Second
+
+	// DefaultPriceStaleAfter is the default time since the latest Price of an instrument was received after which a
+	// PriceCache reports the instrument as stale
+	DefaultPriceStaleAfter = 30 * time.Second
+)
+
+// CachedPrice is the latest Price of an instrument held by a PriceCache
+type CachedPrice struct {
+	ClientPrice
+
+	// The local time the Price was received at.
+	Received time.Time
+}
+
+// Age returns the time elapsed since the Price was received. Unlike the time the Price was created, it is measured
+// with the local clock only, so it is not skewed by the drift between the local clock and OANDA's.
+func (cp CachedPrice) Age() time.Duration {
+	return time.Since(cp.Received)
+}
+
+// PriceStaleness reports an instrument becoming stale or fresh again
+type PriceStaleness struct {
+	// The instrument.
+	Instrument string
+
+	// Whether the instrument has become stale, or fresh again.
+	Stale bool
+
+	// The date/time of the latest Price of the instrument, zero when none was received.
+	LastPrice time.Time
+
+	// The time the staleness was checked at.
+	Time time.Time
+}
+
+// PriceCache holds the latest Price of each of a set of instruments, fed by the pricing stream. While the stream is
+// down, the Prices are polled with GetAccountPricing instead, asking only for the Prices changed since the previous
+// poll, and the stream is reopened every StreamRetryInterval.
+//
+// An instrument is stale when, during MarketHours, its latest Price was received more than StaleAfter ago. The time
+// the Price was received is used rather than the time it was created, which is set by OANDA's clock. The pricing is
+// polled while an instrument is stale, as the stream may have stopped silently.
+type PriceCache struct {
+	// The interval at which the Prices are polled while the pricing stream is down. Default (also used when not
+	// positive): DefaultPricePollInterval
+	PollInterval time.Duration
+
+	// The time the Prices are polled for before reopening the pricing stream. Default (also used when not positive):
+	// DefaultPriceStreamRetryInterval
+	StreamRetryInterval time.Duration
+
+	// The time since the latest Price of an instrument was received after which the instrument is stale. Default
+	// (also used when not positive): DefaultPriceStaleAfter
+	StaleAfter time.Duration
+
+	// Reports whether the market is open at the time, the instruments are never stale while it is closed. Default:
+	// ForexMarketOpen
+	MarketHours func(time.Time) bool
+
+	// Called by Run when an instrument becomes stale, or fresh again after a new Price or once the market closes.
+	OnStaleness func(PriceStaleness)
+
+	client      *Client
+	accountID   AccountID
+	instruments []string
+
+	// Returns the local time, the Prices are received and the staleness and retries are timed at.
+	now func() time.Time
+
+	mu        sync.RWMutex
+	prices    map[string]CachedPrice
+	stale     map[string]bool
+	since     *time.Time
+	lastPoll  time.Time
+	streaming bool
+}
+
+// NewPriceCache creates a PriceCache for the Prices of the instruments in an Account
+func NewPriceCache(client *Client, accountID AccountID, instruments ...string) *PriceCache {
+	return &PriceCache{
+		PollInterval:        DefaultPricePollInterval,
+		StreamRetryInterval: DefaultPriceStreamRetryInterval,
+		StaleAfter:          DefaultPriceStaleAfter,
+		MarketHours:         ForexMarketOpen,
+		client:              client,
+		accountID:           accountID,
+		instruments:         slices.Clone(instruments),
+		now:                 time.Now,
+		prices:              make(map[string]CachedPrice),
+		stale:               make(map[string]bool),
+	}
+}
+
+// Run polls the current Prices and keeps them up to date until the context is done. An error is only returned when
+// the current Prices cannot be polled initially.
+func (pc *PriceCache) Run(ctx context.Context) error {
+	if err := pc.poll(); err != nil {
+		return err
+	}
+	ticker := time.NewTicker(min(time.Second, pc.pollInterval()))
+	defer ticker.Stop()
+	for {
+		prices, stopStream, err := pc.client.openPricingStream(ctx, pc.accountID, pc.instruments)
+		if err == nil {
+			pc.setStreaming(true)
+			err = pc.consume(ctx, prices, ticker.C)
+			stopStream()
+			pc.setStreaming(false)
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err := pc.pollUntil(ctx, pc.now().Add(pc.streamRetryInterval()), ticker.C); err != nil {
+			return err
+		}
+	}
+}
+
+// consume handles the Prices from the stream until it is closed, checking the staleness of the instruments
+func (pc *PriceCache) consume(ctx context.Context, prices <-chan ClientPrice, ticks <-chan time.Time) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticks:
+			now := pc.now()
+			if pc.check(now) {
+				pc.pollIfDue(now)
+			}
+		case price, ok := <-prices:
+			if !ok {
+				return errors.New("pricing stream closed")
+			}
+			pc.HandlePrice(price)
+		}
+	}
+}
+
+// pollUntil polls the Prices until the deadline
+func (pc *PriceCache) pollUntil(ctx context.Context, deadline time.Time, ticks <-chan time.Time) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticks:
+			now := pc.now()
+			pc.pollIfDue(now)
+			pc.check(now)
+			if !now.Before(deadline) {
+				return nil
+			}
+		}
+	}
+}
+
+// pollIfDue polls the Prices when the PollInterval has passed since the previous poll. Failed polls are retried at
+// the next interval, the instruments becoming stale in the meantime.
+func (pc *PriceCache) pollIfDue(now time.Time) {
+	pc.mu.RLock()
+	due := !now.Before(pc.lastPoll.Add(pc.pollInterval()))
+	pc.mu.RUnlock()
+	if due {
+		_ = pc.poll()
+	}
+}
+
+// poll fetches the Prices changed since the previous poll
+func (pc *PriceCache) poll() error {
+	pc.mu.Lock()
+	since := pc.since
+	pc.lastPoll = pc.now()
+	pc.mu.Unlock()
+	response, err := pc.client.GetAccountPricing(pc.accountID, GetAccountPricingRequest{
+		Instruments: pc.instruments,
+		Since:       since,
+	})
+	if err != nil {
+		return err
+	}
+	for _, price := range response.Prices {
+		pc.HandlePrice(price)
+	}
+	pc.mu.Lock()
+	pc.since = &response.Time
+	pc.mu.Unlock()
+	return nil
+}
+
+// pollInterval returns the PollInterval, or the default when it is not positive
+func (pc *PriceCache) pollInterval() time.Duration {
+	if pc.PollInterval <= 0 {
+		return DefaultPricePollInterval
+	}
+	return pc.PollInterval
+}
+
+// streamRetryInterval returns the StreamRetryInterval, or the default when it is not positive
+func (pc *PriceCache) streamRetryInterval() time.Duration {
+	if pc.StreamRetryInterval <= 0 {
+		return DefaultPriceStreamRetryInterval
+	}
+	return pc.StreamRetryInterval
+}
+
+// staleAfter returns the StaleAfter, or the default when it is not positive
+func (pc *PriceCache) staleAfter() time.Duration {
+	if pc.StaleAfter <= 0 {
+		return DefaultPriceStaleAfter
+	}
+	return pc.StaleAfter
+}
+
+func (pc *PriceCache) setStreaming(streaming bool) {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	pc.streaming = streaming
+}
+
+// check checks the staleness of the instruments, reports the changes and returns whether any instrument is stale
+func (pc *PriceCache) check(now time.Time) bool {
+	changes := pc.CheckStaleness(now)
+	if pc.OnStaleness != nil {
+		for _, change := range changes {
+			pc.OnStaleness(change)
+		}
+	}
+	return len(pc.Stale()) > 0
+}
+
+// HandlePrice stores the Price as the latest one of its instrument, unless a newer Price is stored already
+func (pc *PriceCache) HandlePrice(price ClientPrice) {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	if cached, ok := pc.prices[price.Instrument]; ok && cached.Time.After(price.Time) {
+		return
+	}
+	pc.prices[price.Instrument] = CachedPrice{ClientPrice: price, Received: pc.now()}
+}
+
+// CheckStaleness updates the staleness of the instruments at the local time and returns the instruments which became
+// stale or fresh again
+func (pc *PriceCache) CheckStaleness(now time.Time) []PriceStaleness {
+	open := pc.MarketHours == nil || pc.MarketHours(now)
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	var changes []PriceStaleness
+	for _, instrument := range pc.instruments {
+		price, ok := pc.prices[instrument]
+		stale := open && (!ok || now.Sub(price.Received) > pc.staleAfter())
+		if stale != pc.stale[instrument] {
+			pc.stale[instrument] = stale
+			changes = append(changes, PriceStaleness{Instrument: instrument, Stale: stale, LastPrice: price.Time, Time: now})
+		}
+	}
+	return changes
+}
+
+// Get returns the latest Price of the instrument. The second return value is false when no Price of the instrument
+// was received.
+func (pc *PriceCache) Get(instrument string) (CachedPrice, bool) {
+	pc.mu.RLock()
+	defer pc.mu.RUnlock()
+	price, ok := pc.prices[instrument]
+	return price, ok
+}
+
+// Snapshot returns the latest Prices of all the instruments received so far
+func (pc *PriceCache) Snapshot() map[string]CachedPrice {
+	pc.mu.RLock()
+	defer pc.mu.RUnlock()
+	snapshot := make(map[string]CachedPrice, len(pc.prices))
+	for instrument, price := range pc.prices {
+		snapshot[instrument] = price
+	}
+	return snapshot
+}
+
+// Stale returns the instruments found stale by the last staleness check, sorted
+func (pc *PriceCache) Stale() []string {
+	pc.mu.RLock()
+	defer pc.mu.RUnlock()
+	var stale []string
+	for instrument, isStale := range pc.stale {
+		if isStale {
+			stale = append(stale, instrument)
+		}
+	}
+	slices.Sort(stale)
+	return stale
+}
+
+// Streaming returns whether the Prices are currently received from the pricing stream rather than polled
+func (pc *PriceCache) Streaming() bool {
+	pc.mu.RLock()
+	defer pc.mu.RUnlock()
+	return pc.streaming
+}
+
+var newYork = sync.OnceValue(func() *time.Location {
+	location, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		return time.FixedZone("EST", -5*60*60)
+	}
+	return location
+})
+
+// ForexMarketOpen reports whether the forex market is open at the time: from Sunday 17:00 to Friday 17:00 in New
+// York. Holidays are not taken into account.
+func ForexMarketOpen(t time.Time) bool {
+	local := t.In(newYork())
+	switch local.Weekday() {
+	case time.Saturday:
+		return false
+	case time.Sunday:
+		return local.Hour() >= 17
+	case time.Friday:
+		return local.Hour() < 17
+	default:
+		return true
+	}
+}
@@ -0,0 +1,219 @@
+package oanda_sdk
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+var priceCacheStart = time.Date(2024, 6, 4, 12, 0, 0, 0, time.UTC)
+
+// waitFor polls the condition until it holds or the timeout passes
+func waitFor(t *testing.T, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(3 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal("Timed out")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestPriceCachePollsWhileStreamIsDown(t *testing.T) {
+	var mu sync.Mutex
+	var sinces []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v3/accounts/001/pricing":
+			since := r.URL.Query().Get("since")
+			mu.Lock()
+			sinces = append(sinces, since)
+			mu.Unlock()
+			if since == "" {
+				fmt.Fprintf(w, `{"time": "%s", "prices": [{"instrument": "EUR_USD", "time": "%s", "tradeable": true, "bids": [{"price": "1.1010"}], "asks": [{"price": "1.1012"}]}]}`,
+					priceCacheStart.Add(time.Second).Format(time.RFC3339), priceCacheStart.Format(time.RFC3339))
+				return
+			}
+			fmt.Fprintf(w, `{"time": "%s", "prices": [{"instrument": "EUR_USD", "time": "%s", "tradeable": true, "bids": [{"price": "1.1020"}], "asks": [{"price": "1.1023"}]}]}`,
+				priceCacheStart.Add(3*time.Second).Format(time.RFC3339), priceCacheStart.Add(2*time.Second).Format(time.RFC3339))
+		case "/v3/accounts/001/pricing/stream":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			t.Error("Got ", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+	cache := NewPriceCache(NewClient(server.URL, "token", server.Client()), "001", "EUR_USD")
+	cache.PollInterval = 20 * time.Millisecond
+	cache.StreamRetryInterval = time.Hour
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- cache.Run(ctx) }()
+
+	waitFor(t, func() bool {
+		price, ok := cache.Get("EUR_USD")
+		return ok && price.Time.Equal(priceCacheStart.Add(2*time.Second))
+	})
+	cancel()
+	<-done
+
+	price, _ := cache.Get("EUR_USD")
+	spread, ok := price.Spread()
+	if !ok || spread.String() != "0.0003" || !price.Tradeable || cache.Streaming() {
+		t.Error("Got ", price, spread)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(sinces) < 2 || sinces[0] != "" || sinces[1] != priceCacheStart.Add(time.Second).Format(time.RFC3339) {
+		t.Error("Got ", sinces)
+	}
+}
+
+func TestPriceCacheStreamsPrices(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v3/accounts/001/pricing":
+			fmt.Fprintf(w, `{"time": "%s", "prices": [{"instrument": "USD_JPY", "time": "%s", "bids": [{"price": "150.10"}], "asks": [{"price": "150.12"}]}]}`,
+				priceCacheStart.Format(time.RFC3339), priceCacheStart.Format(time.RFC3339))
+		case "/v3/accounts/001/pricing/stream":
+			if r.URL.Query().Get("instruments") != "EUR_USD,USD_JPY" {
+				t.Error("Got ", r.URL.RawQuery)
+			}
+			fmt.Fprintf(w, `{"type": "PRICE", "instrument": "EUR_USD", "time": "%s", "bids": [{"price": "1.1010"}], "asks": [{"price": "1.1011"}]}`+"\n", priceCacheStart.Format(time.RFC3339))
+			// An older Price does not replace the cached one
+			fmt.Fprintf(w, `{"type": "PRICE", "instrument": "USD_JPY", "time": "%s", "bids": [{"price": "149.00"}], "asks": [{"price": "149.02"}]}`+"\n", priceCacheStart.Add(-time.Second).Format(time.RFC3339))
+			w.(http.Flusher).Flush()
+			<-r.Context().Done()
+		default:
+			t.Error("Got ", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+	cache := NewPriceCache(NewClient(server.URL, "token", server.Client()), "001", "EUR_USD", "USD_JPY")
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- cache.Run(ctx) }()
+
+	waitFor(t, func() bool {
+		_, ok := cache.Get("EUR_USD")
+		return ok && cache.Streaming()
+	})
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Error("Got ", err)
+	}
+
+	snapshot := cache.Snapshot()
+	if len(snapshot) != 2 {
+		t.Fatal("Got ", snapshot)
+	}
+	if bid, _ := snapshot["USD_JPY"].BestBid(); bid.String() != "150.1" {
+		t.Error("Got ", snapshot["USD_JPY"])
+	}
+}
+
+func TestPriceCacheStaleness(t *testing.T) {
+	cache := NewPriceCache(nil, "001", "EUR_USD", "USD_JPY")
+	cache.StaleAfter = 10 * time.Second
+	open := true
+	cache.MarketHours = func(time.Time) bool { return open }
+	received := priceCacheStart
+	cache.now = func() time.Time { return received }
+	// OANDA's clock is 20 seconds behind the local one, which does not make the Price stale
+	created := priceCacheStart.Add(-20 * time.Second)
+	cache.HandlePrice(ClientPrice{Instrument: "EUR_USD", Time: created})
+
+	// USD_JPY has no Price at all
+	changes := cache.CheckStaleness(priceCacheStart.Add(5 * time.Second))
+	if len(changes) != 1 || changes[0].Instrument != "USD_JPY" || !changes[0].Stale || !changes[0].LastPrice.IsZero() {
+		t.Error("Got ", changes)
+	}
+	changes = cache.CheckStaleness(priceCacheStart.Add(11 * time.Second))
+	if len(changes) != 1 || changes[0].Instrument != "EUR_USD" || !changes[0].Stale || !changes[0].LastPrice.Equal(created) {
+		t.Error("Got ", changes)
+	}
+	if stale := cache.Stale(); len(stale) != 2 {
+		t.Error("Got ", stale)
+	}
+
+	received = priceCacheStart.Add(12 * time.Second)
+	cache.HandlePrice(ClientPrice{Instrument: "EUR_USD", Time: created.Add(12 * time.Second)})
+	changes = cache.CheckStaleness(priceCacheStart.Add(13 * time.Second))
+	if len(changes) != 1 || changes[0].Instrument != "EUR_USD" || changes[0].Stale {
+		t.Error("Got ", changes)
+	}
+	if price, _ := cache.Get("EUR_USD"); !price.Received.Equal(received) {
+		t.Error("Got ", price.Received)
+	}
+
+	// Nothing is stale while the market is closed
+	open = false
+	changes = cache.CheckStaleness(priceCacheStart.Add(time.Hour))
+	if len(changes) != 1 || changes[0].Instrument != "USD_JPY" || changes[0].Stale || len(cache.Stale()) != 0 {
+		t.Error("Got ", changes)
+	}
+}
+
+func TestForexMarketOpen(t *testing.T) {
+	for _, test := range []struct {
+		time time.Time
+		open bool
+	}{
+		{time.Date(2024, 6, 7, 20, 59, 0, 0, time.UTC), true},  // Friday 16:59 in New York
+		{time.Date(2024, 6, 7, 21, 0, 0, 0, time.UTC), false},  // Friday 17:00 in New York
+		{time.Date(2024, 6, 8, 12, 0, 0, 0, time.UTC), false},  // Saturday
+		{time.Date(2024, 6, 9, 20, 59, 0, 0, time.UTC), false}, // Sunday 16:59 in New York
+		{time.Date(2024, 6, 9, 21, 0, 0, 0, time.UTC), true},   // Sunday 17:00 in New York
+		{time.Date(2024, 1, 7, 22, 0, 0, 0, time.UTC), true},   // Sunday 17:00 in New York in winter
+		{time.Date(2024, 6, 4, 12, 0, 0, 0, time.UTC), true},
+	} {
+		if open := ForexMarketOpen(test.time); open != test.open {
+			t.Error("Got ", test.time, " ", open)
+		}
+	}
+}
+
+func TestPriceCacheRetriesStreamOnItsClock(t *testing.T) {
+	var mu sync.Mutex
+	streams := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v3/accounts/001/pricing":
+			fmt.Fprintf(w, `{"time": "%s", "prices": []}`, priceCacheStart.Format(time.RFC3339))
+		case "/v3/accounts/001/pricing/stream":
+			mu.Lock()
+			streams++
+			mu.Unlock()
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			t.Error("Got ", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+	cache := NewPriceCache(NewClient(server.URL, "token", server.Client()), "001", "EUR_USD")
+	// The defaults are used instead
+	cache.PollInterval, cache.StreamRetryInterval, cache.StaleAfter = 0, -time.Second, 0
+	clock := &fakeClock{now: priceCacheStart}
+	cache.now = clock.Now
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- cache.Run(ctx) }()
+
+	// The stream is reopened once the retry interval has passed on the cache's clock, not on the real one
+	waitFor(t, func() bool {
+		clock.Sleep(context.Background(), DefaultPriceStreamRetryInterval)
+		mu.Lock()
+		defer mu.Unlock()
+		return streams >= 2
+	})
+	cancel()
+	<-done
+	if cache.staleAfter() != DefaultPriceStaleAfter {
+		t.Error("Got ", cache.staleAfter())
+	}
+}
@@ -38,6 +38,65 @@ type ClientPrice struct {
 	// closeout a Position (margin closeout or manual) yet there is no ask
 	// liquidity. The closeout ask is never used to open a new position.
 	CloseoutAsk decimal.Decimal `json:"closeoutAsk"`
+
+	// The factors used to convert quantities of this price’s Instrument’s quote currency into a quantity of the
+	// Account’s home currency. Deprecated by OANDA in favour of the home conversions of the pricing endpoints, but
+	// still provided.
+	QuoteHomeConversionFactors *QuoteHomeConversionFactors `json:"quoteHomeConversionFactors"`
+
+	// Representation of how many units of an Instrument are available to be traded by an Order depending on its
+	// positionFill option. Deprecated by OANDA, but still provided.
+	UnitsAvailable *UnitsAvailable `json:"unitsAvailable"`
+}
+
+// BestBid returns the best (highest) price offered on the bid side of the ClientPrice. The second return value is false
+// when there is currently no bid liquidity.
+func (cp ClientPrice) BestBid() (decimal.Decimal, bool) {
+	if len(cp.Bids) == 0 {
+		return decimal.Zero, false
+	}
+	best := cp.Bids[0].Price
+	for _, bucket := range cp.Bids[1:] {
+		if bucket.Price.GreaterThan(best) {
+			best = bucket.Price
+		}
+	}
+	return best, true
+}
+
+// BestAsk returns the best (lowest) price offered on the ask side of the ClientPrice. The second return value is false
+// when there is currently no ask liquidity.
+func (cp ClientPrice) BestAsk() (decimal.Decimal, bool) {
+	if len(cp.Asks) == 0 {
+		return decimal.Zero, false
+	}
+	best := cp.Asks[0].Price
+	for _, bucket := range cp.Asks[1:] {
+		if bucket.Price.LessThan(best) {
+			best = bucket.Price
+		}
+	}
+	return best, true
+}
+
+// Spread returns the difference between the best ask and the best bid of the ClientPrice. The second return value is
+// false when there is no liquidity on either side.
+func (cp ClientPrice) Spread() (decimal.Decimal, bool) {
+	bid, hasBid := cp.BestBid()
+	ask, hasAsk := cp.BestAsk()
+	if !hasBid || !hasAsk {
+		return decimal.Zero, false
+	}
+	return ask.Sub(bid), true
+}
+
+// ClosingPrice returns the price a Trade of the units would be closed at: the best bid for a long Trade and the best
+// ask for a short Trade. The second return value is false when there is no liquidity on that side.
+func (cp ClientPrice) ClosingPrice(units decimal.Decimal) (decimal.Decimal, bool) {
+	if units.IsNegative() {
+		return cp.BestAsk()
+	}
+	return cp.BestBid()
 }
 
 // QuoteHomeConversionFactors represents the factors that can be used to convert quantities of Price's instrument's
@@ -80,6 +139,15 @@ type HomeConversions struct {
 	PositionValue decimal.Decimal `json:"positionValue"`
 }
 
+// ConvertPL converts a profit/loss in the currency into the home currency, using the AccountGain factor for profits
+// and the AccountLoss factor for losses
+func (hc HomeConversions) ConvertPL(pl decimal.Decimal) decimal.Decimal {
+	if pl.IsNegative() {
+		return pl.Mul(hc.AccountLoss)
+	}
+	return pl.Mul(hc.AccountGain)
+}
+
 // PricingHeartbeat object is injected into the Pricing stream to ensure that the HTTP connection remains active.
 type PricingHeartbeat struct {
 	// The string "HEARTBEAT"
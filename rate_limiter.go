@@ -0,0 +1,48 @@
+package oanda_sdk
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultRateLimit is the number of requests per second a Client sends at most unless configured otherwise. OANDA
+// allows 120 requests per second on a single connection.
+const DefaultRateLimit = 100
+
+// rateLimiter spaces out requests evenly so that no more than the configured number of requests is sent per second
+type rateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+func newRateLimiter(requestsPerSecond int) *rateLimiter {
+	rl := &rateLimiter{}
+	rl.setRate(requestsPerSecond)
+	return rl
+}
+
+// setRate changes the rate of the limiter, a rate of zero or less disables the limiting
+func (rl *rateLimiter) setRate(requestsPerSecond int) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	rl.interval = 0
+	if requestsPerSecond > 0 {
+		rl.interval = time.Second / time.Duration(requestsPerSecond)
+	}
+}
+
+// wait blocks until the next request may be sent
+func (rl *rateLimiter) wait() {
+	rl.mu.Lock()
+	now := time.Now()
+	if rl.next.Before(now) {
+		rl.next = now
+	}
+	delay := rl.next.Sub(now)
+	rl.next = rl.next.Add(rl.interval)
+	rl.mu.Unlock()
+	if delay > 0 {
+		time.Sleep(delay)
+	}
+}
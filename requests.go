@@ -1,6 +1,7 @@
 package oanda_sdk
 
 import (
+	"encoding/json"
 	"github.com/shopspring/decimal"
 	"time"
 )
@@ -38,7 +39,8 @@ type GetInstrumentCandlesRequest struct {
 
 	// A flag that controls whether the candlestick that is covered by the from time should be included in the results.
 	// This flag enables clients to use the timestamp of the last completed candlestick received to poll for future
-	// candlesticks but avoid receiving the previous candlestick repeatedly. [default=True]
+	// candlesticks but avoid receiving the previous candlestick repeatedly (see [Client.SubscribeCandles]).
+	// [default=True]
 	IncludeFirst *bool `url:"includeFirst,omitempty"`
 
 	// The hour of the day (in the specified timezone) to use for granularities that have daily alignments.
@@ -104,6 +106,17 @@ type GetAccountTradesRequest struct {
 	BeforeID *TradeID `url:"beforeID,omitempty"`
 }
 
+type CloseAccountTradeRequest struct {
+	// Indication of how much of the Trade to close. Either the string “ALL” (indicating that all of the Trade should
+	// be closed), or a DecimalNumber representing the number of units of the open Trade to Close using a TradeClose
+	// MarketOrder. The units specified must always be positive, and the magnitude of the value cannot exceed the
+	// magnitude of the Trade’s open units.
+	// Default: ALL
+	Units *string `json:"units,omitempty"`
+}
+
+// UpdateAccountTradeOrdersRequest creates, modifies or cancels the dependent Orders of a Trade. As in the OANDA API, a
+// nil field is sent as null and cancels the Order, unless the corresponding Keep field leaves it unchanged.
 type UpdateAccountTradeOrdersRequest struct {
 	// The specification of the TakeProfit to create/modify/cancel. If
 	// takeProfit is set to null, the TakeProfitOrder will be cancelled if it
@@ -137,28 +150,65 @@ type UpdateAccountTradeOrdersRequest struct {
 	// default value on create, and be inherited by the replacing order on
 	// modify.
 	GuaranteedStopLoss *GuaranteedStopLossDetails `json:"guaranteedStopLoss"`
+
+	// Leave the existing TakeProfitOrder unchanged when TakeProfit is nil, by not providing takeProfit
+	KeepTakeProfit bool `json:"-"`
+
+	// Leave the existing StopLossOrder unchanged when StopLoss is nil, by not providing stopLoss
+	KeepStopLoss bool `json:"-"`
+
+	// Leave the existing TrailingStopLossOrder unchanged when TrailingStopLoss is nil, by not providing
+	// trailingStopLoss
+	KeepTrailingStopLoss bool `json:"-"`
+
+	// Leave the existing GuaranteedStopLossOrder unchanged when GuaranteedStopLoss is nil, by not providing
+	// guaranteedStopLoss
+	KeepGuaranteedStopLoss bool `json:"-"`
+}
+
+// MarshalJSON sends null for the nil dependent Orders, except for the ones kept unchanged which are not provided
+func (r UpdateAccountTradeOrdersRequest) MarshalJSON() ([]byte, error) {
+	field := func(details any, isNil, keep bool) any {
+		switch {
+		case !isNil:
+			return details
+		case keep:
+			// A nil interface is omitted
+			return nil
+		}
+		return json.RawMessage("null")
+	}
+	return json.Marshal(struct {
+		TakeProfit         any `json:"takeProfit,omitempty"`
+		StopLoss           any `json:"stopLoss,omitempty"`
+		TrailingStopLoss   any `json:"trailingStopLoss,omitempty"`
+		GuaranteedStopLoss any `json:"guaranteedStopLoss,omitempty"`
+	}{
+		TakeProfit:         field(r.TakeProfit, r.TakeProfit == nil, r.KeepTakeProfit),
+		StopLoss:           field(r.StopLoss, r.StopLoss == nil, r.KeepStopLoss),
+		TrailingStopLoss:   field(r.TrailingStopLoss, r.TrailingStopLoss == nil, r.KeepTrailingStopLoss),
+		GuaranteedStopLoss: field(r.GuaranteedStopLoss, r.GuaranteedStopLoss == nil, r.KeepGuaranteedStopLoss),
+	})
 }
 
 type CloseAccountInstrumentPositionRequest struct {
-	// Indication of how much of the long Position to closeout. Either the
-	// string “ALL”, the string “NONE”, or a DecimalNumber representing how many
-	// units of the long position to close using a PositionCloseout MarketOrder.
-	// The units specified must always be positive.
+	// Indication of how much of the long Position to closeout: ALL, NONE, or
+	// the number of units of the long position to close using a
+	// PositionCloseout MarketOrder.
 	// Default: ALL
-	LongUnits *string `json:"longUnits,omitempty"`
+	LongUnits CloseUnits `json:"longUnits,omitempty"`
 
 	// The client extensions to add to the MarketOrder used to close the long position.
-	LongClientExtensions ClientExtensions `json:"longClientExtensions"`
+	LongClientExtensions *ClientExtensions `json:"longClientExtensions,omitempty"`
 
-	// Indication of how much of the short Position to closeout. Either the
-	// string “ALL”, the string “NONE”, or a DecimalNumber representing how many
-	// units of the short position to close using a PositionCloseout
-	// MarketOrder. The units specified must always be positive.
+	// Indication of how much of the short Position to closeout: ALL, NONE, or
+	// the number of units of the short position to close using a
+	// PositionCloseout MarketOrder.
 	// Default: ALL
-	ShortUnits *string `json:"shortUnits,omitempty"`
+	ShortUnits CloseUnits `json:"shortUnits,omitempty"`
 
 	// The client extensions to add to the MarketOrder used to close the short position.
-	ShortClientExtensions ClientExtensions
+	ShortClientExtensions *ClientExtensions `json:"shortClientExtensions,omitempty"`
 }
 
 type GetAccountTransactionsRequest struct {
@@ -1,7 +1,11 @@
 package oanda_sdk
 
 import (
+	"encoding/json"
 	"github.com/google/go-querystring/query"
+	"github.com/shopspring/decimal"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 )
@@ -359,3 +363,123 @@ func TestGetAccountPricingStreamRequestIntoQueryStringSimple(t *testing.T) {
 		t.Error("Got ", urlQuery.Encode())
 	}
 }
+
+func TestSpecifiersAreEscapedInPath(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.EscapedPath())
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"errorMessage": "not found"}`))
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, "token", server.Client())
+	client.CancelAccountOrder("001", OrderByClientID("grid/eur usd#1"))
+	client.GetAccountTrade("001", TradeByClientID("swing?1"))
+	client.GetAccountOrder("001", OrderByID("1234"))
+	if len(paths) != 3 ||
+		paths[0] != "/v3/accounts/001/orders/@grid%2Feur%20usd%231/cancel" ||
+		paths[1] != "/v3/accounts/001/trades/@swing%3F1" ||
+		paths[2] != "/v3/accounts/001/orders/1234" {
+		t.Error("Got ", paths)
+	}
+	if clientID, ok := OrderByClientID("grid-1").ClientID(); !ok || clientID != "grid-1" {
+		t.Error("Got ", clientID)
+	}
+	if _, ok := TradeByID("42").ClientID(); ok {
+		t.Error("Got a client ID for a trade specified by its ID")
+	}
+}
+
+func TestUpdateAccountTradeOrdersRequestKeepsUnchangedOrders(t *testing.T) {
+	price := decimal.RequireFromString("1.1")
+	body, err := json.Marshal(UpdateAccountTradeOrdersRequest{
+		StopLoss:             &StopLossDetails{Price: &price},
+		KeepTrailingStopLoss: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The nil Orders not kept are cancelled, as in the OANDA API
+	if string(body) != `{"takeProfit":null,"stopLoss":{"price":"1.1"},"guaranteedStopLoss":null}` {
+		t.Error("Got ", string(body))
+	}
+	// Unset client extensions are not sent
+	body, err = json.Marshal(UpdateAccountTradeOrdersRequest{
+		TrailingStopLoss: &TrailingStopLossDetails{Distance: price},
+		KeepTakeProfit:   true, KeepStopLoss: true, KeepGuaranteedStopLoss: true,
+	})
+	if err != nil || string(body) != `{"trailingStopLoss":{"distance":"1.1"}}` {
+		t.Error("Got ", string(body), err)
+	}
+	// A set Order is sent even when kept
+	body, err = json.Marshal(UpdateAccountTradeOrdersRequest{StopLoss: &StopLossDetails{Price: &price}, KeepStopLoss: true})
+	if err != nil || string(body) != `{"takeProfit":null,"stopLoss":{"price":"1.1"},"trailingStopLoss":null,"guaranteedStopLoss":null}` {
+		t.Error("Got ", string(body), err)
+	}
+	body, err = json.Marshal(UpdateAccountTradeOrdersRequest{})
+	if err != nil || string(body) != `{"takeProfit":null,"stopLoss":null,"trailingStopLoss":null,"guaranteedStopLoss":null}` {
+		t.Error("Got ", string(body), err)
+	}
+}
+
+func TestClientEncodesQueryParameters(t *testing.T) {
+	queries := make(map[string]string)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries[r.URL.Path] = r.URL.RawQuery
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, "token", server.Client())
+	snapshot := time.Date(2024, 6, 3, 12, 20, 0, 0, time.UTC)
+	price := PricingComponent("BA")
+	granularity := H1
+
+	_, _ = client.GetAccountChanges("001", "42")
+	_, _ = client.GetInstrumentOrderBook("EUR_USD", &snapshot)
+	_, _ = client.GetInstrumentPositionBook("USD_JPY", &snapshot)
+	_, _ = client.GetAccountTransactions("001", GetAccountTransactionsRequest{From: &snapshot})
+	_, _ = client.GetAccountTransactionsByIdRange("001", GetAccountTransactionsByIdRangeRequest{From: "1", To: "9", Type: []TransactionFilter{"ORDER", "FUNDING"}})
+	_, _ = client.GetAccountLatestCandles("001", GetAccountLatestCandlesRequest{CandleSpecifications: []CandleSpecification{"EUR_USD:S10:BM", "USD_JPY:M1:M"}})
+	_, _ = client.GetAccountPricing("001", GetAccountPricingRequest{Instruments: []string{"EUR_USD", "USD_JPY"}, Since: &snapshot})
+	_, _ = client.GetAccountInstrumentCandles("001", "EUR_USD", GetAccountInstrumentCandlesRequest{Price: &price, Granularity: &granularity})
+
+	for path, expected := range map[string]string{
+		"/v3/accounts/001/changes":                     "sinceTransactionID=42",
+		"/v3/instruments/EUR_USD/orderBook":            "time=2024-06-03T12%3A20%3A00Z",
+		"/v3/instruments/USD_JPY/positionBook":         "time=2024-06-03T12%3A20%3A00Z",
+		"/v3/accounts/001/transactions":                "from=2024-06-03T12%3A20%3A00Z",
+		"/v3/accounts/001/transactions/idrange":        "from=1&to=9&type=ORDER%2CFUNDING",
+		"/v3/accounts/001/candles/latest":              "candleSpecifications=EUR_USD%3AS10%3ABM%2CUSD_JPY%3AM1%3AM",
+		"/v3/accounts/001/pricing":                     "instruments=EUR_USD%2CUSD_JPY&since=2024-06-03T12%3A20%3A00Z",
+		"/v3/accounts/001/instruments/EUR_USD/candles": "granularity=H1&price=BA",
+	} {
+		if queries[path] != expected {
+			t.Error("Got ", path, " ", queries[path])
+		}
+	}
+}
+
+func TestCloseAccountTradeClosesAllUnits(t *testing.T) {
+	var bodies []map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Error("Got ", err)
+		}
+		bodies = append(bodies, body)
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, "token", server.Client())
+	if _, err := client.CloseAccountTrade("001", TradeByID("42")); err != nil {
+		t.Fatal(err)
+	}
+	units := "100"
+	if _, err := client.CloseAccountTradeUnits("001", TradeByID("42"), CloseAccountTradeRequest{Units: &units}); err != nil {
+		t.Fatal(err)
+	}
+	// No units closes the whole Trade
+	if len(bodies) != 2 || len(bodies[0]) != 0 || bodies[1]["units"] != "100" {
+		t.Error("Got ", bodies)
+	}
+}
@@ -1,6 +1,9 @@
 package oanda_sdk
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 type GetAccountsResponse struct {
 	// The list of Accounts the client is authorized to access and their associated properties.
@@ -126,10 +129,57 @@ type CreateOrderErrorResponse struct {
 	ErrorMessage string `json:"errorMessage"`
 }
 
+func (r *CreateOrderResponse) UnmarshalJSON(data []byte) error {
+	type plain CreateOrderResponse
+	aux := struct {
+		*plain
+		OrderCreateTransaction        json.RawMessage `json:"orderCreateTransaction"`
+		OrderReissueTransaction       json.RawMessage `json:"orderReissueTransaction"`
+		OrderReissueRejectTransaction json.RawMessage `json:"orderReissueRejectTransaction"`
+	}{plain: (*plain)(r)}
+	err := json.Unmarshal(data, &aux)
+	if err != nil {
+		return err
+	}
+	r.OrderCreateTransaction, err = UnmarshalTransaction(aux.OrderCreateTransaction)
+	if err != nil {
+		return err
+	}
+	r.OrderReissueTransaction, err = unmarshalOptionalTransaction(aux.OrderReissueTransaction)
+	if err != nil {
+		return err
+	}
+	r.OrderReissueRejectTransaction, err = unmarshalOptionalTransaction(aux.OrderReissueRejectTransaction)
+	return err
+}
+
 func (er CreateOrderErrorResponse) Error() string {
 	return er.ErrorMessage
 }
 
+func (er *CreateOrderErrorResponse) UnmarshalJSON(data []byte) error {
+	type plain CreateOrderErrorResponse
+	aux := struct {
+		*plain
+		OrderRejectTransaction json.RawMessage `json:"orderRejectTransaction"`
+	}{plain: (*plain)(er)}
+	err := json.Unmarshal(data, &aux)
+	if err != nil {
+		return err
+	}
+	er.OrderRejectTransaction, err = UnmarshalTransaction(aux.OrderRejectTransaction)
+	return err
+}
+
+// unmarshalOptionalTransaction decodes a Transaction which is only provided in some responses
+func unmarshalOptionalTransaction(data []byte) (*Transaction, error) {
+	transaction, err := UnmarshalTransaction(data)
+	if err != nil || transaction == nil {
+		return nil, err
+	}
+	return &transaction, nil
+}
+
 type GetAccountOrdersResponse struct {
 	// The list of Order detail objects
 	Orders []Order `json:"orders"`
@@ -138,6 +188,20 @@ type GetAccountOrdersResponse struct {
 	LastTransactionID TransactionID `json:"lastTransactionID"`
 }
 
+func (r *GetAccountOrdersResponse) UnmarshalJSON(data []byte) error {
+	type plain GetAccountOrdersResponse
+	aux := struct {
+		*plain
+		Orders json.RawMessage `json:"orders"`
+	}{plain: (*plain)(r)}
+	err := json.Unmarshal(data, &aux)
+	if err != nil {
+		return err
+	}
+	r.Orders, err = unmarshalOrders(aux.Orders)
+	return err
+}
+
 type GetAccountOrderResponse struct {
 	// The details of the Order requested
 	Order Order `json:"order"`
@@ -146,6 +210,20 @@ type GetAccountOrderResponse struct {
 	LastTransactionID TransactionID `json:"lastTransactionID"`
 }
 
+func (r *GetAccountOrderResponse) UnmarshalJSON(data []byte) error {
+	type plain GetAccountOrderResponse
+	aux := struct {
+		*plain
+		Order json.RawMessage `json:"order"`
+	}{plain: (*plain)(r)}
+	err := json.Unmarshal(data, &aux)
+	if err != nil {
+		return err
+	}
+	r.Order, err = UnmarshalOrder(aux.Order)
+	return err
+}
+
 type ReplaceAccountOrderResponse struct {
 	// The Transaction that cancelled the Order to be replaced.
 	OrderCancelTransaction OrderCancelTransaction `json:"orderCancelTransaction"`
@@ -174,6 +252,30 @@ type ReplaceAccountOrderResponse struct {
 	LastTransactionID TransactionID `json:"lastTransactionID"`
 }
 
+func (r *ReplaceAccountOrderResponse) UnmarshalJSON(data []byte) error {
+	type plain ReplaceAccountOrderResponse
+	aux := struct {
+		*plain
+		OrderCreateTransaction        json.RawMessage `json:"orderCreateTransaction"`
+		OrderReissueTransaction       json.RawMessage `json:"orderReissueTransaction"`
+		OrderReissueRejectTransaction json.RawMessage `json:"orderReissueRejectTransaction"`
+	}{plain: (*plain)(r)}
+	err := json.Unmarshal(data, &aux)
+	if err != nil {
+		return err
+	}
+	r.OrderCreateTransaction, err = UnmarshalTransaction(aux.OrderCreateTransaction)
+	if err != nil {
+		return err
+	}
+	r.OrderReissueTransaction, err = unmarshalOptionalTransaction(aux.OrderReissueTransaction)
+	if err != nil {
+		return err
+	}
+	r.OrderReissueRejectTransaction, err = unmarshalOptionalTransaction(aux.OrderReissueRejectTransaction)
+	return err
+}
+
 type ReplaceAccountOrderErrorResponse struct {
 	// The Transaction that rejected the cancellation of the Order to be replaced. Only present if the Account exists.
 	OrderCancelRejectTransaction *Transaction `json:"orderCancelRejectTransaction"`
@@ -195,6 +297,20 @@ func (er ReplaceAccountOrderErrorResponse) Error() string {
 	return er.ErrorMessage
 }
 
+func (er *ReplaceAccountOrderErrorResponse) UnmarshalJSON(data []byte) error {
+	type plain ReplaceAccountOrderErrorResponse
+	aux := struct {
+		*plain
+		OrderCancelRejectTransaction json.RawMessage `json:"orderCancelRejectTransaction"`
+	}{plain: (*plain)(er)}
+	err := json.Unmarshal(data, &aux)
+	if err != nil {
+		return err
+	}
+	er.OrderCancelRejectTransaction, err = unmarshalOptionalTransaction(aux.OrderCancelRejectTransaction)
+	return err
+}
+
 type CancelAccountOrderResponse struct {
 	// The Transaction that cancelled the Order
 	OrderCancelTransaction OrderCancelTransaction `json:"orderCancelTransaction"`
@@ -21,3 +21,29 @@ func TestGetAccountResponseUnmarshalling(t *testing.T) {
 		t.Errorf("ResettablePLTime should be nil")
 	}
 }
+
+func TestCreateOrderResponseUnmarshalling(t *testing.T) {
+	file, err := os.Open("test/createOrderResponse.json")
+	if err != nil {
+		t.Error(err)
+	}
+	defer file.Close()
+	var createOrderResponse CreateOrderResponse
+	err = json.NewDecoder(file).Decode(&createOrderResponse)
+	if err != nil {
+		t.Fatal(err)
+	}
+	orderCreateTransaction, ok := createOrderResponse.OrderCreateTransaction.(MarketOrderTransaction)
+	if !ok {
+		t.Fatalf("Got %T", createOrderResponse.OrderCreateTransaction)
+	}
+	if orderCreateTransaction.Id != "6356" || orderCreateTransaction.ClientExtensions.Id != "my_order" {
+		t.Error("Got ", orderCreateTransaction)
+	}
+	if createOrderResponse.OrderFillTransaction == nil || createOrderResponse.OrderFillTransaction.TradeOpened.TradeID != "6357" {
+		t.Error("Got ", createOrderResponse.OrderFillTransaction)
+	}
+	if createOrderResponse.OrderReissueTransaction != nil {
+		t.Error("OrderReissueTransaction should be nil")
+	}
+}
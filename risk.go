@@ -0,0 +1,281 @@
+package oanda_sdk
+
+import (
+	"fmt"
+	"github.com/shopspring/decimal"
+	"slices"
+	"strings"
+	"time"
+)
+
+// RiskRule is a pre-trade check run by the Client before an OrderRequest is sent by CreateOrder or
+// ReplaceAccountOrder. Returning an error stops the OrderRequest from being sent. Rules report broken limits with a
+// *RiskViolation; any other error means the rule could not be evaluated, and the OrderRequest is not sent either.
+type RiskRule interface {
+	Check(check RiskCheck) error
+}
+
+// RiskRuleFunc adapts a function to the RiskRule interface
+type RiskRuleFunc func(check RiskCheck) error
+
+func (f RiskRuleFunc) Check(check RiskCheck) error {
+	return f(check)
+}
+
+// RiskViolation is the error returned when an OrderRequest breaks a RiskRule
+type RiskViolation struct {
+	// The name of the broken rule.
+	Rule string
+
+	// The description of the violation.
+	Reason string
+
+	// The OrderRequest which was not sent.
+	OrderRequest OrderRequest
+}
+
+func (rv *RiskViolation) Error() string {
+	return fmt.Sprintf("risk rule %s violated: %s", rv.Rule, rv.Reason)
+}
+
+// RiskCheck describes an OrderRequest about to be sent to the RiskRules
+type RiskCheck struct {
+	// The Client sending the OrderRequest, for rules which need to look up the state of the Account.
+	Client *Client
+
+	// The Account the OrderRequest is sent to.
+	AccountID AccountID
+
+	// The OrderRequest to be sent.
+	OrderRequest OrderRequest
+
+	// The Order replaced by the OrderRequest, nil when a new Order is created.
+	Replaces *OrderSpecifier
+
+	// The time the OrderRequest is sent at.
+	Time time.Time
+
+	// The instrument of the OrderRequest. Empty for Orders dependent on a Trade, which can only reduce a Position.
+	Instrument string
+
+	// The units of the OrderRequest.
+	Units decimal.Decimal
+
+	// The price threshold of the OrderRequest, nil for MarketOrders.
+	Price *decimal.Decimal
+
+	// Whether the OrderRequest creates a stop loss (regular, guaranteed or trailing) for the Trade it opens.
+	HasStopLoss bool
+}
+
+// Opening reports whether the OrderRequest may open or increase a Position. Orders dependent on a Trade can not.
+func (rc RiskCheck) Opening() bool {
+	return rc.Instrument != ""
+}
+
+// AddRiskRules adds rules to be checked before every OrderRequest sent by the Client. The rules are run in the order
+// they were added; the first failing rule stops the OrderRequest.
+func (c *Client) AddRiskRules(rules ...RiskRule) {
+	c.riskMu.Lock()
+	defer c.riskMu.Unlock()
+	c.riskRules = append(c.riskRules, rules...)
+}
+
+// checkRisk runs the RiskRules of the Client against the OrderRequest
+func (c *Client) checkRisk(accountID AccountID, orderRequest OrderRequest, replaces *OrderSpecifier) error {
+	c.riskMu.RLock()
+	rules := c.riskRules
+	c.riskMu.RUnlock()
+	if len(rules) == 0 {
+		return nil
+	}
+	check := RiskCheck{
+		Client:       c,
+		AccountID:    accountID,
+		OrderRequest: orderRequest,
+		Replaces:     replaces,
+		Time:         time.Now(),
+	}
+	describeOrderRequest(&check, orderRequest)
+	for _, rule := range rules {
+		if err := rule.Check(check); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// describeOrderRequest fills in the RiskCheck with the details of the OrderRequest
+func describeOrderRequest(check *RiskCheck, orderRequest OrderRequest) {
+	switch r := orderRequest.(type) {
+	case MarketOrderRequest:
+		check.Instrument, check.Units = r.Instrument, r.Units
+		check.HasStopLoss = r.StopLossOnFill != nil || r.GuaranteedStopLossOnFill != nil || r.TrailingStopLossOnFill != nil
+	case LimitOrderRequest:
+		check.Instrument, check.Units, check.Price = r.Instrument, r.Units, &r.Price
+		check.HasStopLoss = r.StopLossOnFill != nil || r.GuaranteedStopLossOnFill != nil || r.TrailingStopLossOnFill != nil
+	case StopOrderRequest:
+		check.Instrument, check.Units, check.Price = r.Instrument, r.Units, &r.Price
+		check.HasStopLoss = r.StopLossOnFill != nil || r.GuaranteedStopLossOnFill != nil || r.TrailingStopLossOnFill != nil
+	case MarketIfTouchedOrderRequest:
+		check.Instrument, check.Units, check.Price = r.Instrument, r.Units, &r.Price
+		check.HasStopLoss = r.StopLossOnFill != nil || r.GuaranteedStopLossOnFill != nil || r.TrailingStopLossOnFill != nil
+	case *MarketOrderRequest:
+		describeOrderRequest(check, *r)
+	case *LimitOrderRequest:
+		describeOrderRequest(check, *r)
+	case *StopOrderRequest:
+		describeOrderRequest(check, *r)
+	case *MarketIfTouchedOrderRequest:
+		describeOrderRequest(check, *r)
+	}
+}
+
+// MaxUnits limits the absolute net units of the Position per instrument, including the units of the Order. The open
+// Position is looked up for every Order of a limited instrument; pending Orders are not taken into account. Instruments
+// missing from the limits are not limited.
+func MaxUnits(limits map[string]decimal.Decimal) RiskRule {
+	return RiskRuleFunc(func(check RiskCheck) error {
+		limit, ok := limits[check.Instrument]
+		if !ok {
+			return nil
+		}
+		position, err := check.Client.GetAccountInstrumentPosition(check.AccountID, check.Instrument)
+		if err != nil {
+			return err
+		}
+		units := position.Position.Long.Units.Add(position.Position.Short.Units).Add(check.Units)
+		if units.Abs().LessThanOrEqual(limit) {
+			return nil
+		}
+		return &RiskViolation{
+			Rule:         "MaxUnits",
+			Reason:       fmt.Sprintf("position of %s units of %s would exceed the limit of %s", units, check.Instrument, limit),
+			OrderRequest: check.OrderRequest,
+		}
+	})
+}
+
+// MaxNotional limits the notional value of a single Order in the Account's home currency. The value is converted from
+// the quote currency of the instrument using the home conversion factors of the current price, which is also used as
+// the price of MarketOrders.
+func MaxNotional(limit decimal.Decimal) RiskRule {
+	return RiskRuleFunc(func(check RiskCheck) error {
+		if !check.Opening() {
+			return nil
+		}
+		notional, err := homeNotional(check)
+		if err != nil {
+			return err
+		}
+		if notional.LessThanOrEqual(limit) {
+			return nil
+		}
+		return &RiskViolation{
+			Rule:         "MaxNotional",
+			Reason:       fmt.Sprintf("notional of %s in home currency exceeds the limit of %s", notional.StringFixed(2), limit),
+			OrderRequest: check.OrderRequest,
+		}
+	})
+}
+
+func homeNotional(check RiskCheck) (decimal.Decimal, error) {
+	_, quote, ok := strings.Cut(check.Instrument, "_")
+	if !ok {
+		return decimal.Zero, fmt.Errorf("cannot determine the quote currency of %s", check.Instrument)
+	}
+	includeHomeConversion := true
+	pricing, err := check.Client.GetAccountPricing(check.AccountID, GetAccountPricingRequest{
+		Instruments:           []string{check.Instrument},
+		IncludeHomeConversion: &includeHomeConversion,
+	})
+	if err != nil {
+		return decimal.Zero, err
+	}
+	price := check.Price
+	if price == nil {
+		for _, clientPrice := range pricing.Prices {
+			if clientPrice.Instrument != check.Instrument {
+				continue
+			}
+			best, ok := clientPrice.BestBid()
+			if check.Units.IsPositive() {
+				best, ok = clientPrice.BestAsk()
+			}
+			if ok {
+				price = &best
+			}
+		}
+	}
+	if price == nil {
+		return decimal.Zero, fmt.Errorf("no price available for %s", check.Instrument)
+	}
+	for _, conversion := range pricing.HomeConversions {
+		if string(conversion.Currency) == quote {
+			return check.Units.Abs().Mul(*price).Mul(conversion.PositionValue), nil
+		}
+	}
+	return decimal.Zero, fmt.Errorf("no home conversion available for %s", quote)
+}
+
+// MaxOpenTrades limits the number of Trades open in the Account. Orders which may open a Trade are refused once the
+// limit is reached.
+func MaxOpenTrades(limit int) RiskRule {
+	return RiskRuleFunc(func(check RiskCheck) error {
+		if !check.Opening() {
+			return nil
+		}
+		summary, err := check.Client.GetAccountSummary(check.AccountID)
+		if err != nil {
+			return err
+		}
+		if summary.Account.OpenTradeCount < limit {
+			return nil
+		}
+		return &RiskViolation{
+			Rule:         "MaxOpenTrades",
+			Reason:       fmt.Sprintf("%d trades are open, the limit is %d", summary.Account.OpenTradeCount, limit),
+			OrderRequest: check.OrderRequest,
+		}
+	})
+}
+
+// RequireStopLoss refuses Orders which may open a Trade without creating a stop loss for it
+func RequireStopLoss() RiskRule {
+	return RiskRuleFunc(func(check RiskCheck) error {
+		if !check.Opening() || check.HasStopLoss {
+			return nil
+		}
+		return &RiskViolation{
+			Rule:         "RequireStopLoss",
+			Reason:       fmt.Sprintf("order for %s has no stop loss on fill", check.Instrument),
+			OrderRequest: check.OrderRequest,
+		}
+	})
+}
+
+// TradingHours only allows Orders to be sent on the given weekdays between start and end, both being offsets from
+// midnight in the location. An end before the start allows trading over midnight. No weekdays allow every day.
+// Orders dependent on a Trade are allowed at any time, so that Positions can always be protected.
+func TradingHours(location *time.Location, start, end time.Duration, weekdays ...time.Weekday) RiskRule {
+	return RiskRuleFunc(func(check RiskCheck) error {
+		if !check.Opening() {
+			return nil
+		}
+		now := check.Time.In(location)
+		midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, location)
+		offset := now.Sub(midnight)
+		allowed := offset >= start && offset < end
+		if end < start {
+			allowed = offset >= start || offset < end
+		}
+		if allowed && (len(weekdays) == 0 || slices.Contains(weekdays, now.Weekday())) {
+			return nil
+		}
+		return &RiskViolation{
+			Rule:         "TradingHours",
+			Reason:       fmt.Sprintf("trading is not allowed at %s", now.Format(time.RFC1123)),
+			OrderRequest: check.OrderRequest,
+		}
+	})
+}
@@ -0,0 +1,118 @@
+package oanda_sdk
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestRiskViolationSendsNothing(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v3/accounts/001/positions/EUR_USD" {
+			w.Write([]byte(`{"position": {"instrument": "EUR_USD", "long": {"units": "0"}, "short": {"units": "0"}}}`))
+			return
+		}
+		requests++
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, "token", server.Client())
+	client.AddRiskRules(MaxUnits(map[string]decimal.Decimal{"EUR_USD": decimal.NewFromInt(1000)}), RequireStopLoss())
+
+	_, err := client.CreateOrder("001", MarketOrderRequest{Instrument: "EUR_USD", Units: decimal.NewFromInt(-5000)})
+	var violation *RiskViolation
+	if !errors.As(err, &violation) || violation.Rule != "MaxUnits" {
+		t.Error("Got ", err)
+	}
+	_, err = client.ReplaceAccountOrder("001", "10", &LimitOrderRequest{Instrument: "EUR_USD", Units: decimal.NewFromInt(500)})
+	if !errors.As(err, &violation) || violation.Rule != "RequireStopLoss" {
+		t.Error("Got ", err)
+	}
+	if requests != 0 {
+		t.Error("Got ", requests)
+	}
+
+	// Orders dependent on a Trade are not subject to the rules of entry Orders
+	_, err = client.CreateOrder("001", StopLossOrderRequest{TradeID: "90"})
+	if errors.As(err, &violation) || requests != 1 {
+		t.Error("Got ", err, requests)
+	}
+}
+
+func TestMaxUnitsIncludesOpenPosition(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v3/accounts/001/positions/EUR_USD" {
+			t.Error("Got ", r.URL.Path)
+		}
+		w.Write([]byte(`{"position": {"instrument": "EUR_USD", "long": {"units": "800"}, "short": {"units": "0"}}}`))
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, "token", server.Client())
+	rule := MaxUnits(map[string]decimal.Decimal{"EUR_USD": decimal.NewFromInt(1000)})
+
+	check := RiskCheck{Client: client, AccountID: "001", Instrument: "EUR_USD", Units: decimal.NewFromInt(300)}
+	var violation *RiskViolation
+	if err := rule.Check(check); !errors.As(err, &violation) {
+		t.Error("Got ", err)
+	}
+	// Reducing the Position is within the limit, reversing it beyond the limit is not
+	check.Units = decimal.NewFromInt(-1500)
+	if err := rule.Check(check); err != nil {
+		t.Error("Got ", err)
+	}
+	check.Units = decimal.NewFromInt(-1900)
+	if err := rule.Check(check); !errors.As(err, &violation) {
+		t.Error("Got ", err)
+	}
+	check.Instrument = "USD_JPY"
+	if err := rule.Check(check); err != nil {
+		t.Error("Got ", err)
+	}
+}
+
+func TestMaxNotionalConvertsToHomeCurrency(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("includeHomeConversion") != "true" {
+			t.Error("Got ", r.URL.RawQuery)
+		}
+		w.Write([]byte(`{
+			"prices": [{"instrument": "USD_JPY", "bids": [{"price": "150.00"}], "asks": [{"price": "150.02"}]}],
+			"homeConversions": [{"currency": "JPY", "positionValue": "0.0066"}, {"currency": "USD", "positionValue": "1"}]
+		}`))
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, "token", server.Client())
+	rule := MaxNotional(decimal.NewFromInt(10000))
+
+	// 1000 * 150.02 * 0.0066 = 990.132
+	check := RiskCheck{Client: client, AccountID: "001", Instrument: "USD_JPY", Units: decimal.NewFromInt(1000)}
+	if err := rule.Check(check); err != nil {
+		t.Error("Got ", err)
+	}
+	check.Units = decimal.NewFromInt(-20000)
+	var violation *RiskViolation
+	if err := rule.Check(check); !errors.As(err, &violation) {
+		t.Error("Got ", err)
+	}
+}
+
+func TestTradingHours(t *testing.T) {
+	rule := TradingHours(time.UTC, 22*time.Hour, 6*time.Hour, time.Monday, time.Tuesday)
+	for at, allowed := range map[string]bool{
+		"2024-03-04T23:00:00Z": true,
+		"2024-03-05T05:59:00Z": true,
+		"2024-03-05T12:00:00Z": false,
+		"2024-03-06T01:00:00Z": false,
+	} {
+		now, _ := time.Parse(time.RFC3339, at)
+		err := rule.Check(RiskCheck{Instrument: "EUR_USD", Time: now})
+		if (err == nil) != allowed {
+			t.Error("Got ", at, err)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package oanda_sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/shopspring/decimal"
+	"sort"
+)
+
+// ScaleOutTranche is a part of a Trade closed once the market reaches a price
+type ScaleOutTranche struct {
+	// The price at which the tranche is closed. A long Trade is reduced once the bid reaches the price, a short Trade
+	// once the ask reaches it.
+	Price decimal.Decimal
+
+	// The positive number of units to close. Zero, or more units than are open, close the rest of the Trade.
+	Units decimal.Decimal
+}
+
+// ScaleOutResult is the outcome of closing a single ScaleOutTranche
+type ScaleOutResult struct {
+	// The closed tranche.
+	Tranche ScaleOutTranche
+
+	// How the Trade was reduced by the tranche, only set if the closing MarketOrder was filled.
+	TradeReduce *TradeReduce
+
+	// The response to closing the tranche.
+	Response *CloseAccountTradeResponse
+
+	// The reason closing the tranche failed.
+	Err error
+}
+
+// ScaleOutReport describes the progress of a scale-out of a Trade
+type ScaleOutReport struct {
+	// The ID of the Trade.
+	TradeID TradeID
+
+	// The outcome of each tranche closed so far, in the order they were closed.
+	Results []ScaleOutResult
+
+	// The units of the Trade still open.
+	RemainingUnits decimal.Decimal
+
+	// The PL realized by the tranches closed so far.
+	RealizedPL decimal.Decimal
+}
+
+// ScaleOut closes a Trade in tranches as the prices from the channel (typically the one returned by
+// [Client.GetAccountPricingStream]) reach the prices of the tranches. It returns once every tranche is closed, the
+// Trade is fully closed, closing a tranche fails, or the context is done. The report is returned in every case.
+func (c *Client) ScaleOut(ctx context.Context, accountID AccountID, tradeSpecifier TradeSpecifier, tranches []ScaleOutTranche, prices <-chan ClientPrice) (*ScaleOutReport, error) {
+	trade, err := c.GetAccountTrade(accountID, tradeSpecifier)
+	if err != nil {
+		return nil, err
+	}
+	report := &ScaleOutReport{TradeID: trade.Trade.Id, RemainingUnits: trade.Trade.CurrentUnits.Abs()}
+	long := trade.Trade.CurrentUnits.IsPositive()
+	pending := append([]ScaleOutTranche{}, tranches...)
+	// The tranches are closed in the order the market reaches them
+	sort.SliceStable(pending, func(i, j int) bool {
+		if long {
+			return pending[i].Price.LessThan(pending[j].Price)
+		}
+		return pending[i].Price.GreaterThan(pending[j].Price)
+	})
+	for len(pending) > 0 && report.RemainingUnits.IsPositive() {
+		var price ClientPrice
+		select {
+		case <-ctx.Done():
+			return report, ctx.Err()
+		case p, ok := <-prices:
+			if !ok {
+				return report, errors.New("pricing stream closed")
+			}
+			price = p
+		}
+		if price.Instrument != trade.Trade.Instrument || !price.Tradeable {
+			continue
+		}
+		current, ok := price.BestAsk()
+		if long {
+			current, ok = price.BestBid()
+		}
+		for ok && len(pending) > 0 && report.RemainingUnits.IsPositive() {
+			tranche := pending[0]
+			if (long && current.LessThan(tranche.Price)) || (!long && current.GreaterThan(tranche.Price)) {
+				break
+			}
+			pending = pending[1:]
+			result := c.closeTranche(accountID, report, tranche)
+			report.Results = append(report.Results, result)
+			if result.Err != nil {
+				return report, fmt.Errorf("closing tranche at %s: %w", tranche.Price, result.Err)
+			}
+		}
+	}
+	return report, nil
+}
+
+func (c *Client) closeTranche(accountID AccountID, report *ScaleOutReport, tranche ScaleOutTranche) ScaleOutResult {
+	units := "ALL"
+	if tranche.Units.IsPositive() && tranche.Units.LessThan(report.RemainingUnits) {
+		units = tranche.Units.String()
+	}
+	response, err := c.CloseAccountTradeUnits(accountID, TradeByID(report.TradeID), CloseAccountTradeRequest{Units: &units})
+	result := ScaleOutResult{Tranche: tranche, Response: response, Err: err}
+	if err != nil {
+		return result
+	}
+	if tradeReduce, ok := response.OrderFillTransaction.TradeReduceFor(report.TradeID); ok {
+		result.TradeReduce = &tradeReduce
+		report.RemainingUnits = report.RemainingUnits.Sub(tradeReduce.Units.Abs())
+		report.RealizedPL = report.RealizedPL.Add(tradeReduce.RealizedPL)
+	} else {
+		result.Err = errors.New("the trade closing order was not filled")
+	}
+	return result
+}
@@ -0,0 +1,62 @@
+package oanda_sdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestScaleOutClosesTranchesAtPrices(t *testing.T) {
+	var closed []string
+	open := decimal.NewFromInt(300)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v3/accounts/001/trades/42":
+			fmt.Fprintf(w, `{"trade": {"id": "42", "instrument": "EUR_USD", "currentUnits": "%s"}}`, open)
+		case "/v3/accounts/001/trades/42/close":
+			var request CloseAccountTradeRequest
+			if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.Units == nil {
+				t.Error("Got ", request, err)
+				return
+			}
+			closed = append(closed, *request.Units)
+			units := open
+			if *request.Units != "ALL" {
+				units = decimal.RequireFromString(*request.Units)
+			}
+			open = open.Sub(units)
+			fmt.Fprintf(w, `{"orderFillTransaction": {"id": "50", "type": "ORDER_FILL", "tradeReduced": {"tradeID": "42", "units": "-%s", "realizedPL": "1.5"}}}`, units)
+		default:
+			t.Error("Got ", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, "token", server.Client())
+	prices := make(chan ClientPrice, 3)
+	for _, bid := range []string{"1.0990", "1.1010", "1.1060"} {
+		prices <- ClientPrice{
+			Instrument: "EUR_USD",
+			Tradeable:  true,
+			Bids:       []PriceBucket{{Price: decimal.RequireFromString(bid)}},
+		}
+	}
+	report, err := client.ScaleOut(context.Background(), "001", "42", []ScaleOutTranche{
+		{Price: decimal.RequireFromString("1.1050")},
+		{Price: decimal.RequireFromString("1.1000"), Units: decimal.NewFromInt(100)},
+		{Price: decimal.RequireFromString("1.1020"), Units: decimal.NewFromInt(100)},
+	}, prices)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(closed) != "[100 100 ALL]" {
+		t.Error("Got ", closed)
+	}
+	if !report.RemainingUnits.IsZero() || !report.RealizedPL.Equal(decimal.RequireFromString("4.5")) || len(report.Results) != 3 {
+		t.Error("Got ", report)
+	}
+}
@@ -0,0 +1,138 @@
+package oanda_sdk
+
+import (
+	"errors"
+	"github.com/shopspring/decimal"
+)
+
+// PositionSizeLimit names the limit a position size was clipped to
+type PositionSizeLimit string
+
+const (
+	// PositionSizeLimitNone means the position size was not clipped
+	PositionSizeLimitNone = PositionSizeLimit("")
+
+	// PositionSizeLimitMinimumTradeSize means the position size was raised to the MinimumTradeSize of the Instrument
+	PositionSizeLimitMinimumTradeSize = PositionSizeLimit("MINIMUM_TRADE_SIZE")
+
+	// PositionSizeLimitMaximumOrderUnits means the position size was lowered to the MaximumOrderUnits of the Instrument
+	PositionSizeLimitMaximumOrderUnits = PositionSizeLimit("MAXIMUM_ORDER_UNITS")
+
+	// PositionSizeLimitUnitsAvailable means the position size was lowered to the units available in the Account
+	PositionSizeLimitUnitsAvailable = PositionSizeLimit("UNITS_AVAILABLE")
+)
+
+// PositionSizing describes a Trade to be sized so that hitting its stop loss loses a given share of the Account's NAV
+type PositionSizing struct {
+	// The net asset value of the Account, typically AccountSummary.NAV.
+	NAV decimal.Decimal
+
+	// The percentage of the NAV to risk, e.g. 1 for 1%.
+	RiskPercent decimal.Decimal
+
+	// The distance of the stop loss from the entry price, in pips.
+	StopDistancePips decimal.Decimal
+
+	// Whether the Trade is long (true) or short (false).
+	Long bool
+
+	// The Instrument to trade.
+	Instrument Instrument
+
+	// The current price of the Instrument. Its UnitsAvailable, if provided, limit the position size.
+	Price ClientPrice
+
+	// The factors converting the quote currency of the Instrument into the home currency of the Account. The factors
+	// of the Price are used when not set.
+	QuoteHomeConversionFactors *QuoteHomeConversionFactors
+
+	// The positionFill option of the Order, selecting the relevant UnitsAvailable. Default: DEFAULT
+	PositionFill OrderPositionFill
+}
+
+// PositionSize is the result of sizing a Trade
+type PositionSize struct {
+	// The units to trade, negative for a short Trade. Zero when no units are available.
+	Units decimal.Decimal
+
+	// The amount in the home currency lost when the stop loss is hit with the Units. It differs from the risk asked
+	// for when the units were clipped or truncated.
+	Risk decimal.Decimal
+
+	// The limit the Units were clipped to.
+	Limit PositionSizeLimit
+}
+
+// SizePosition calculates the units of a Trade risking the given percentage of the NAV with the given stop distance.
+// The units are truncated to the TradeUnitsPrecision of the Instrument and clipped to its MinimumTradeSize and
+// MaximumOrderUnits, and to the units available in the Account for the direction and positionFill of the Trade.
+//
+// Clipping to the MinimumTradeSize makes the Trade risk more than asked for, which is reflected in the Risk of the
+// result.
+func SizePosition(sizing PositionSizing) (*PositionSize, error) {
+	if !sizing.StopDistancePips.IsPositive() {
+		return nil, errors.New("stop distance must be positive")
+	}
+	if !sizing.RiskPercent.IsPositive() || !sizing.NAV.IsPositive() {
+		return nil, errors.New("risk percent and NAV must be positive")
+	}
+	factors := sizing.QuoteHomeConversionFactors
+	if factors == nil {
+		factors = sizing.Price.QuoteHomeConversionFactors
+	}
+	if factors == nil {
+		return nil, errors.New("no quote home conversion factors available")
+	}
+	// Hitting the stop loss is a loss, i.e. a negative amount of the quote currency
+	factor := factors.NegativeUnits
+	if !factor.IsPositive() {
+		return nil, errors.New("invalid quote home conversion factor")
+	}
+
+	riskAmount := sizing.NAV.Mul(sizing.RiskPercent).Div(decimal.NewFromInt(100))
+	stopDistance := sizing.StopDistancePips.Shift(int32(sizing.Instrument.PipLocation))
+	lossPerUnit := stopDistance.Mul(factor)
+	precision := int32(sizing.Instrument.TradeUnitsPrecision)
+	units := riskAmount.Div(lossPerUnit).Truncate(precision)
+
+	limit := PositionSizeLimitNone
+	if maximum := sizing.Instrument.MaximumOrderUnits; maximum.IsPositive() && units.GreaterThan(maximum) {
+		units, limit = maximum, PositionSizeLimitMaximumOrderUnits
+	}
+	if minimum := sizing.Instrument.MinimumTradeSize; units.LessThan(minimum) {
+		units, limit = minimum, PositionSizeLimitMinimumTradeSize
+	}
+	if available, ok := unitsAvailable(sizing); ok && units.GreaterThan(available) {
+		units, limit = available.Truncate(precision), PositionSizeLimitUnitsAvailable
+		if units.LessThan(sizing.Instrument.MinimumTradeSize) {
+			units = decimal.Zero
+		}
+	}
+
+	size := &PositionSize{Units: units, Risk: units.Mul(lossPerUnit), Limit: limit}
+	if !sizing.Long {
+		size.Units = size.Units.Neg()
+	}
+	return size, nil
+}
+
+// unitsAvailable selects the units available for the direction and positionFill of the sized Trade
+func unitsAvailable(sizing PositionSizing) (decimal.Decimal, bool) {
+	available := sizing.Price.UnitsAvailable
+	if available == nil {
+		return decimal.Zero, false
+	}
+	details := available.Default
+	switch sizing.PositionFill {
+	case OrderPositionFillOpenOnly:
+		details = available.OpenOnly
+	case OrderPositionFillReduceFirst:
+		details = available.ReduceFirst
+	case OrderPositionFillReduceOnly:
+		details = available.ReduceOnly
+	}
+	if sizing.Long {
+		return details.Long.Abs(), true
+	}
+	return details.Short.Abs(), true
+}
@@ -0,0 +1,49 @@
+package oanda_sdk
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestSizePosition(t *testing.T) {
+	d := decimal.RequireFromString
+	sizing := PositionSizing{
+		NAV:              d("10000"),
+		RiskPercent:      d("1"),
+		StopDistancePips: d("20"),
+		Long:             true,
+		Instrument:       Instrument{Name: "USD_JPY", PipLocation: -2, MinimumTradeSize: d("1"), MaximumOrderUnits: d("100000000")},
+		Price: ClientPrice{
+			QuoteHomeConversionFactors: &QuoteHomeConversionFactors{PositiveUnits: d("0.0068"), NegativeUnits: d("0.0067")},
+		},
+	}
+	// 100 / (0.20 * 0.0067) = 74626.86...
+	size, err := SizePosition(sizing)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !size.Units.Equal(d("74626")) || size.Limit != PositionSizeLimitNone || !size.Risk.Equal(d("99.99884")) {
+		t.Error("Got ", size)
+	}
+
+	sizing.Instrument.MaximumOrderUnits = d("50000")
+	size, _ = SizePosition(sizing)
+	if !size.Units.Equal(d("50000")) || size.Limit != PositionSizeLimitMaximumOrderUnits {
+		t.Error("Got ", size)
+	}
+
+	sizing.Long = false
+	sizing.Price.UnitsAvailable = &UnitsAvailable{Default: UnitsAvailableDetails{Long: d("90000"), Short: d("30000.5")}}
+	size, _ = SizePosition(sizing)
+	if !size.Units.Equal(d("-30000")) || size.Limit != PositionSizeLimitUnitsAvailable {
+		t.Error("Got ", size)
+	}
+
+	sizing.NAV = d("0.01")
+	sizing.Price.UnitsAvailable = nil
+	size, _ = SizePosition(sizing)
+	if !size.Units.Equal(d("-1")) || size.Limit != PositionSizeLimitMinimumTradeSize {
+		t.Error("Got ", size)
+	}
+}
@@ -0,0 +1,414 @@
+package oanda_sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/shopspring/decimal"
+	"sync"
+	"time"
+)
+
+// ErrTradeNotFound is returned by UpdateAccountTradeOrders when the Trade does not exist, e.g. because it was closed
+var ErrTradeNotFound = errors.New("trade not found")
+
+// DefaultStopUpdateInterval is the default minimum interval between two updates of the stop loss of a Trade by a
+// StopManager
+const DefaultStopUpdateInterval = 5 * time.Second
+
+// StopCheck is the state of a Trade a StopRule computes the stop loss for
+type StopCheck struct {
+	// The Trade.
+	Trade Trade
+
+	// Whether the Trade is long.
+	Long bool
+
+	// The price the Trade would be closed at: the bid for a long Trade and the ask for a short Trade.
+	Price decimal.Decimal
+
+	// The price of the current StopLossOrder of the Trade, nil when the Trade has none.
+	StopLoss *decimal.Decimal
+
+	// The size of a pip of the Trade's instrument.
+	PipSize decimal.Decimal
+}
+
+// Gain returns the distance the price has moved in favour of the Trade since it was opened, negative when the Trade
+// is at a loss
+func (sc StopCheck) Gain() decimal.Decimal {
+	if sc.Long {
+		return sc.Price.Sub(sc.Trade.Price)
+	}
+	return sc.Trade.Price.Sub(sc.Price)
+}
+
+// FromOpen returns the price the distance away from the open price of the Trade in its favour, or against it for a
+// negative distance
+func (sc StopCheck) FromOpen(distance decimal.Decimal) decimal.Decimal {
+	if sc.Long {
+		return sc.Trade.Price.Add(distance)
+	}
+	return sc.Trade.Price.Sub(distance)
+}
+
+// FromPrice returns the price the distance away from the current price against the Trade
+func (sc StopCheck) FromPrice(distance decimal.Decimal) decimal.Decimal {
+	if sc.Long {
+		return sc.Price.Sub(distance)
+	}
+	return sc.Price.Add(distance)
+}
+
+// StopRule computes the stop loss of a Trade managed by a StopManager
+type StopRule interface {
+	// StopLoss returns the price the StopLossOrder of the Trade should be at. The second return value is false when
+	// the rule does not apply to the Trade (yet).
+	StopLoss(check StopCheck) (decimal.Decimal, bool)
+}
+
+// The StopRuleFunc type is an adapter to allow the use of ordinary functions as StopRules
+type StopRuleFunc func(check StopCheck) (decimal.Decimal, bool)
+
+// StopLoss calls f(check)
+func (f StopRuleFunc) StopLoss(check StopCheck) (decimal.Decimal, bool) {
+	return f(check)
+}
+
+// BreakevenStop moves the stop loss of a Trade to its open price once the price has moved triggerPips in its favour.
+// The stop loss is placed lockPips beyond the open price in favour of the Trade, locking in a profit.
+func BreakevenStop(triggerPips, lockPips decimal.Decimal) StopRule {
+	return StopRuleFunc(func(check StopCheck) (decimal.Decimal, bool) {
+		if check.Gain().LessThan(triggerPips.Mul(check.PipSize)) {
+			return decimal.Zero, false
+		}
+		return check.FromOpen(lockPips.Mul(check.PipSize)), true
+	})
+}
+
+// StepTrailingStop keeps the stop loss of a Trade distancePips from its open price and moves it by stepPips in favour
+// of the Trade each time the price has moved another stepPips in its favour
+func StepTrailingStop(distancePips, stepPips decimal.Decimal) StopRule {
+	return StopRuleFunc(func(check StopCheck) (decimal.Decimal, bool) {
+		step := stepPips.Mul(check.PipSize)
+		if !step.IsPositive() {
+			return decimal.Zero, false
+		}
+		steps := decimal.Max(check.Gain().Div(step).Floor(), decimal.Zero)
+		return check.FromOpen(steps.Mul(step).Sub(distancePips.Mul(check.PipSize))), true
+	})
+}
+
+// ATRTrailingStop trails the stop loss of a Trade a multiple of the average true range of its instrument behind the
+// price. The average true range is computed from the mid candlesticks of the instrument and recomputed once per
+// candlestick. The rule does not apply to a Trade while the candlesticks of its instrument cannot be fetched.
+type ATRTrailingStop struct {
+	// The granularity of the candlesticks.
+	Granularity CandlestickGranularity
+
+	// The number of candlesticks the average true range is computed over.
+	Period int
+
+	// The multiple of the average true range the stop loss trails the price by.
+	Multiplier decimal.Decimal
+
+	client *Client
+	mu     sync.Mutex
+	ranges map[string]averageTrueRange
+}
+
+type averageTrueRange struct {
+	value   decimal.Decimal
+	expires time.Time
+}
+
+// NewATRTrailingStop creates an ATRTrailingStop fetching the candlesticks with the client
+func NewATRTrailingStop(client *Client, granularity CandlestickGranularity, period int, multiplier decimal.Decimal) *ATRTrailingStop {
+	return &ATRTrailingStop{
+		Granularity: granularity,
+		Period:      period,
+		Multiplier:  multiplier,
+		client:      client,
+		ranges:      make(map[string]averageTrueRange),
+	}
+}
+
+// StopLoss places the stop loss the multiple of the average true range behind the current price
+func (s *ATRTrailingStop) StopLoss(check StopCheck) (decimal.Decimal, bool) {
+	atr, err := s.averageTrueRange(check.Trade.Instrument)
+	if err != nil {
+		return decimal.Zero, false
+	}
+	return check.FromPrice(atr.Mul(s.Multiplier)), true
+}
+
+func (s *ATRTrailingStop) averageTrueRange(instrument string) (decimal.Decimal, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if cached, ok := s.ranges[instrument]; ok && time.Now().Before(cached.expires) {
+		return cached.value, nil
+	}
+	atr, err := s.client.GetAverageTrueRange(instrument, s.Granularity, s.Period)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	s.ranges[instrument] = averageTrueRange{value: atr, expires: time.Now().Add(s.Granularity.Duration())}
+	return atr, nil
+}
+
+// StopUpdate is the outcome of moving the stop loss of a Trade
+type StopUpdate struct {
+	// The ID of the Trade.
+	TradeID TradeID
+
+	// The new price of the StopLossOrder.
+	StopLoss decimal.Decimal
+
+	// The price of the previous StopLossOrder, nil when the Trade had none.
+	Previous *decimal.Decimal
+
+	// The response to the update, only set if the StopLossOrder was moved.
+	Response *UpdateAccountTradeOrdersResponse
+
+	// The reason the update failed.
+	Err error
+}
+
+// StopManager moves the StopLossOrders of Trades according to StopRules as prices arrive. The stop loss of a Trade
+// is only ever tightened: when several rules apply, the one closest to the price wins, and it replaces the current
+// stop loss only if it is closer to the price too.
+//
+// The updates of each Trade are debounced: the stop loss of a Trade is updated at most once per MinUpdateInterval,
+// with the latest stop loss computed in the meantime.
+type StopManager struct {
+	// The minimum interval between two updates of the stop loss of a Trade. Default (also used when not positive):
+	// DefaultStopUpdateInterval
+	MinUpdateInterval time.Duration
+
+	// Called with the outcome of every update of a stop loss, if set.
+	OnUpdate func(StopUpdate)
+
+	client    *Client
+	accountID AccountID
+	rules     []StopRule
+
+	mu          sync.Mutex
+	trades      map[TradeID]*managedTrade
+	instruments map[string]Instrument
+}
+
+type managedTrade struct {
+	trade      Trade
+	stopLoss   *decimal.Decimal
+	pending    *decimal.Decimal
+	lastUpdate time.Time
+}
+
+// NewStopManager creates a StopManager applying the rules to the Trades of an Account
+func NewStopManager(client *Client, accountID AccountID, rules ...StopRule) *StopManager {
+	return &StopManager{
+		MinUpdateInterval: DefaultStopUpdateInterval,
+		client:            client,
+		accountID:         accountID,
+		rules:             rules,
+		trades:            make(map[TradeID]*managedTrade),
+		instruments:       make(map[string]Instrument),
+	}
+}
+
+// Manage starts managing the stop loss of the open Trades. The Instruments of the Trades are loaded if needed.
+func (sm *StopManager) Manage(trades ...Trade) error {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	var missing []string
+	for _, trade := range trades {
+		if _, ok := sm.instruments[trade.Instrument]; !ok {
+			missing = append(missing, trade.Instrument)
+		}
+	}
+	if len(missing) > 0 {
+		response, err := sm.client.GetAccountInstruments(sm.accountID, missing)
+		if err != nil {
+			return err
+		}
+		for _, instrument := range response.Instruments {
+			sm.instruments[instrument.Name] = instrument
+		}
+	}
+	for _, trade := range trades {
+		if _, ok := sm.instruments[trade.Instrument]; !ok {
+			return fmt.Errorf("unknown instrument %s", trade.Instrument)
+		}
+	}
+	for _, trade := range trades {
+		managed := &managedTrade{trade: trade}
+		if trade.StopLossOrder != nil {
+			stopLoss := trade.StopLossOrder.Price
+			managed.stopLoss = &stopLoss
+		}
+		sm.trades[trade.Id] = managed
+	}
+	return nil
+}
+
+// ManageOpenTrades starts managing the stop loss of all open Trades of the Account
+func (sm *StopManager) ManageOpenTrades() error {
+	response, err := sm.client.GetAccountOpenTrades(sm.accountID)
+	if err != nil {
+		return err
+	}
+	return sm.Manage(response.Trades...)
+}
+
+// Forget stops managing the stop loss of a Trade
+func (sm *StopManager) Forget(tradeID TradeID) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	delete(sm.trades, tradeID)
+}
+
+// Run feeds the prices from the channel (typically the one returned by [Client.GetAccountPricingStream]) into the
+// StopManager and sends the stop loss updates until the context is done or the channel is closed. A Trade which was
+// closed in the meantime, or is closed by its new stop loss, is no longer managed. A stop loss rejected by OANDA is
+// dropped, while one which failed to be sent (e.g. a timeout or a server error) is retried after the
+// MinUpdateInterval, unless a tighter one was computed in the meantime.
+func (sm *StopManager) Run(ctx context.Context, prices <-chan ClientPrice) error {
+	ticker := time.NewTicker(sm.minUpdateInterval())
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case now := <-ticker.C:
+			sm.flush(now)
+		case price, ok := <-prices:
+			if !ok {
+				return errors.New("pricing stream closed")
+			}
+			sm.HandlePrice(price)
+			sm.flush(time.Now())
+		}
+	}
+}
+
+// HandlePrice computes the stop loss of the managed Trades of the Price's instrument. The stop loss is only sent by
+// Run, subject to the MinUpdateInterval.
+func (sm *StopManager) HandlePrice(price ClientPrice) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	for _, managed := range sm.trades {
+		if managed.trade.Instrument != price.Instrument {
+			continue
+		}
+		instrument := sm.instruments[price.Instrument]
+		if stopLoss, ok := sm.stopLoss(managed, instrument, price); ok {
+			managed.pending = &stopLoss
+		}
+	}
+}
+
+// stopLoss computes the stop loss of a Trade, it is false when the stop loss should not be moved
+func (sm *StopManager) stopLoss(managed *managedTrade, instrument Instrument, price ClientPrice) (decimal.Decimal, bool) {
+	long := managed.trade.CurrentUnits.IsPositive()
+	closePrice, ok := price.ClosingPrice(managed.trade.CurrentUnits)
+	if !ok {
+		return decimal.Zero, false
+	}
+	check := StopCheck{
+		Trade:    managed.trade,
+		Long:     long,
+		Price:    closePrice,
+		StopLoss: managed.stopLoss,
+		PipSize:  decimal.New(1, int32(instrument.PipLocation)),
+	}
+	// tighter reports whether a is closer to the price than b
+	tighter := func(a, b decimal.Decimal) bool {
+		if long {
+			return a.GreaterThan(b)
+		}
+		return a.LessThan(b)
+	}
+	var best *decimal.Decimal
+	for _, rule := range sm.rules {
+		stopLoss, ok := rule.StopLoss(check)
+		if ok && (best == nil || tighter(stopLoss, *best)) {
+			best = &stopLoss
+		}
+	}
+	if best == nil {
+		return decimal.Zero, false
+	}
+	// Round away from the price, so that rounding never moves the stop loss past the price
+	precision := int32(instrument.DisplayPrecision)
+	stopLoss := best.RoundFloor(precision)
+	if !long {
+		stopLoss = best.RoundCeil(precision)
+	}
+	if !tighter(closePrice, stopLoss) {
+		return decimal.Zero, false
+	}
+	current := managed.stopLoss
+	if managed.pending != nil {
+		current = managed.pending
+	}
+	if current != nil && !tighter(stopLoss, *current) {
+		return decimal.Zero, false
+	}
+	return stopLoss, true
+}
+
+// minUpdateInterval returns the MinUpdateInterval, or the default when it is not positive
+func (sm *StopManager) minUpdateInterval() time.Duration {
+	if sm.MinUpdateInterval <= 0 {
+		return DefaultStopUpdateInterval
+	}
+	return sm.MinUpdateInterval
+}
+
+// flush sends the pending stop losses of the Trades not updated within the MinUpdateInterval
+func (sm *StopManager) flush(now time.Time) {
+	type update struct {
+		tradeID            TradeID
+		stopLoss, previous *decimal.Decimal
+	}
+	var updates []update
+	sm.mu.Lock()
+	for tradeID, managed := range sm.trades {
+		if managed.pending != nil && now.Sub(managed.lastUpdate) >= sm.minUpdateInterval() {
+			updates = append(updates, update{tradeID: tradeID, stopLoss: managed.pending, previous: managed.stopLoss})
+			managed.pending = nil
+			managed.lastUpdate = now
+		}
+	}
+	sm.mu.Unlock()
+
+	for _, u := range updates {
+		response, err := sm.client.UpdateAccountTradeOrders(sm.accountID, TradeByID(u.tradeID), UpdateAccountTradeOrdersRequest{
+			StopLoss:               &StopLossDetails{Price: u.stopLoss},
+			KeepTakeProfit:         true,
+			KeepTrailingStopLoss:   true,
+			KeepGuaranteedStopLoss: true,
+		})
+		closed := errors.Is(err, ErrTradeNotFound)
+		if err == nil && response.StopLossOrderFillTransaction != nil {
+			err = errors.New("the trade was closed by its new stop loss")
+			closed = true
+		}
+		var rejected UpdateAccountTradeOrdersErrorResponse
+		sm.mu.Lock()
+		if managed, ok := sm.trades[u.tradeID]; ok {
+			switch {
+			case closed:
+				delete(sm.trades, u.tradeID)
+			case err == nil:
+				managed.stopLoss = u.stopLoss
+			case !errors.As(err, &rejected) && managed.pending == nil:
+				managed.pending = u.stopLoss
+			}
+		}
+		sm.mu.Unlock()
+		if sm.OnUpdate != nil {
+			sm.OnUpdate(StopUpdate{TradeID: u.tradeID, StopLoss: *u.stopLoss, Previous: u.previous, Response: response, Err: err})
+		}
+	}
+}
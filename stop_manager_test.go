@@ -0,0 +1,135 @@
+package oanda_sdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestStopManagerTightensAndDebouncesStopLoss(t *testing.T) {
+	var updates []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v3/accounts/001/instruments":
+			fmt.Fprint(w, `{"instruments": [{"name": "EUR_USD", "pipLocation": -4, "displayPrecision": 5}]}`)
+		case "/v3/accounts/001/trades/42/orders":
+			var body map[string]json.RawMessage
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Error("Got ", err)
+			}
+			if _, ok := body["takeProfit"]; ok || len(body) != 1 {
+				t.Error("Got ", body)
+			}
+			updates = append(updates, string(body["stopLoss"]))
+			fmt.Fprint(w, `{"stopLossOrderTransaction": {"id": "50", "type": "STOP_LOSS_ORDER"}}`)
+		default:
+			t.Error("Got ", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, "token", server.Client())
+	manager := NewStopManager(client, "001",
+		BreakevenStop(decimal.NewFromInt(10), decimal.NewFromInt(1)),
+		StepTrailingStop(decimal.NewFromInt(50), decimal.NewFromInt(20)),
+	)
+	var outcomes []StopUpdate
+	manager.OnUpdate = func(update StopUpdate) {
+		outcomes = append(outcomes, update)
+	}
+	err := manager.Manage(Trade{
+		Id:            "42",
+		Instrument:    "EUR_USD",
+		Price:         decimal.RequireFromString("1.1000"),
+		CurrentUnits:  decimal.NewFromInt(100),
+		StopLossOrder: &StopLossOrder{Price: decimal.RequireFromString("1.0950")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	tick := func(bid string, at time.Duration) {
+		manager.HandlePrice(ClientPrice{Instrument: "EUR_USD", Bids: []PriceBucket{{Price: decimal.RequireFromString(bid)}}})
+		manager.flush(start.Add(at))
+	}
+	tick("1.1005", 0)             // no rule tightens the stop loss
+	tick("1.1015", 0)             // breakeven
+	tick("1.1045", time.Second)   // the step trail is still below breakeven
+	tick("1.1075", 2*time.Second) // the step trail is debounced
+	if fmt.Sprint(updates) != `[{"price":"1.1001"}]` {
+		t.Error("Got ", updates)
+	}
+	manager.flush(start.Add(DefaultStopUpdateInterval))
+	if fmt.Sprint(updates) != `[{"price":"1.1001"} {"price":"1.101"}]` {
+		t.Error("Got ", updates)
+	}
+	if len(outcomes) != 2 || outcomes[1].Previous == nil || outcomes[1].Previous.String() != "1.1001" || outcomes[1].Err != nil {
+		t.Error("Got ", outcomes)
+	}
+}
+
+func TestStopManagerRetriesFailedUpdates(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v3/accounts/001/instruments":
+			fmt.Fprint(w, `{"instruments": [{"name": "EUR_USD", "pipLocation": -4, "displayPrecision": 5}]}`)
+		case "/v3/accounts/001/trades/42/orders":
+			attempts++
+			if attempts == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			fmt.Fprint(w, `{"stopLossOrderTransaction": {"id": "50", "type": "STOP_LOSS_ORDER"}}`)
+		case "/v3/accounts/001/trades/43/orders":
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"errorMessage": "The Trade specified does not exist"}`)
+		default:
+			t.Error("Got ", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, "token", server.Client())
+	manager := NewStopManager(client, "001", BreakevenStop(decimal.NewFromInt(10), decimal.NewFromInt(1)))
+	var outcomes []StopUpdate
+	manager.OnUpdate = func(update StopUpdate) {
+		outcomes = append(outcomes, update)
+	}
+	trade := func(id TradeID) Trade {
+		return Trade{Id: id, Instrument: "EUR_USD", Price: decimal.RequireFromString("1.1000"), CurrentUnits: decimal.NewFromInt(100)}
+	}
+	if err := manager.Manage(trade("42"), trade("43")); err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	manager.HandlePrice(ClientPrice{Instrument: "EUR_USD", Bids: []PriceBucket{{Price: decimal.RequireFromString("1.1015")}}})
+	manager.flush(start)
+	if len(outcomes) != 2 || outcomes[0].Err == nil || outcomes[1].Err == nil {
+		t.Fatal("Got ", outcomes)
+	}
+	// The closed Trade is forgotten, the failed update is retried after the interval
+	manager.flush(start.Add(DefaultStopUpdateInterval))
+	if attempts != 2 || len(outcomes) != 3 || outcomes[2].TradeID != "42" || outcomes[2].Err != nil || outcomes[2].StopLoss.String() != "1.1001" {
+		t.Error("Got ", attempts, outcomes)
+	}
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+	if _, ok := manager.trades["43"]; ok || len(manager.trades) != 1 {
+		t.Error("Got ", manager.trades)
+	}
+}
+
+func TestStopManagerDefaultsNonPositiveInterval(t *testing.T) {
+	manager := NewStopManager(nil, "001")
+	manager.MinUpdateInterval = 0
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := manager.Run(ctx, nil); err != context.Canceled || manager.minUpdateInterval() != DefaultStopUpdateInterval {
+		t.Error("Got ", err)
+	}
+}
@@ -2,6 +2,7 @@ package oanda_sdk
 
 import (
 	"github.com/shopspring/decimal"
+	"strings"
 	"time"
 )
 
@@ -46,6 +47,23 @@ const (
 // Example: @my_trade_id
 type TradeSpecifier string
 
+// TradeByID specifies a Trade by its OANDA-assigned TradeID
+func TradeByID(tradeID TradeID) TradeSpecifier {
+	return TradeSpecifier(tradeID)
+}
+
+// TradeByClientID specifies a Trade by the ClientID the client has assigned to it
+func TradeByClientID(clientID ClientID) TradeSpecifier {
+	return TradeSpecifier("@" + clientID)
+}
+
+// ClientID returns the ClientID of a Trade specified by its ClientID. The second return value is false when the Trade
+// is specified by its TradeID.
+func (ts TradeSpecifier) ClientID() (ClientID, bool) {
+	clientID, ok := strings.CutPrefix(string(ts), "@")
+	return ClientID(clientID), ok
+}
+
 // Trade struct specifies a Trade within an Account. This includes the full representation of the Trade's dependent
 // Orders in addition to the IDs of those Orders.
 type Trade struct {
@@ -0,0 +1,55 @@
+package oanda_sdk
+
+import (
+	"iter"
+	"strconv"
+	"time"
+)
+
+// maxTradesPageSize is the maximum number of Trades OANDA returns per request
+const maxTradesPageSize = 500
+
+// TradesIter walks the Trades of an Account matching the request, from the most recent one, fetching the pages lazily
+// as the iteration proceeds. The Count of the request sets the page size (default and maximum: 500, also used for a
+// Count of zero or less) and its BeforeID the starting point. The iteration ends at the first Trade opened before
+// since, unless since is zero.
+//
+// An error fetching a page is yielded once and ends the iteration.
+func (c *Client) TradesIter(accountID AccountID, request GetAccountTradesRequest, since time.Time) iter.Seq2[Trade, error] {
+	return func(yield func(Trade, error) bool) {
+		// A page shorter than the page size is the last one, so the page size must not exceed what OANDA returns
+		if request.Count == nil || *request.Count <= 0 || *request.Count > maxTradesPageSize {
+			count := maxTradesPageSize
+			request.Count = &count
+		}
+		for {
+			response, err := c.GetAccountTrades(accountID, request)
+			if err != nil {
+				yield(Trade{}, err)
+				return
+			}
+			for _, trade := range response.Trades {
+				if !since.IsZero() && trade.OpenTime.Before(since) {
+					return
+				}
+				if !yield(trade, nil) {
+					return
+				}
+			}
+			if len(response.Trades) == 0 || len(response.Trades) < *request.Count {
+				return
+			}
+			// The Trades are listed from the most recent one, the next page ends right before the oldest Trade listed
+			oldest, err := strconv.ParseInt(string(response.Trades[len(response.Trades)-1].Id), 10, 64)
+			if err != nil {
+				yield(Trade{}, err)
+				return
+			}
+			if oldest <= 1 {
+				return
+			}
+			beforeID := TradeID(strconv.FormatInt(oldest-1, 10))
+			request.BeforeID = &beforeID
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package oanda_sdk
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTradesPagingServer serves the Trades 1 to last, returning at most maxTradesPageSize Trades per page like OANDA
+func newTradesPagingServer(t *testing.T, requests *[]string, last int) *httptest.Server {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		*requests = append(*requests, r.URL.RawQuery)
+		if query.Get("state") != "CLOSED" || query.Get("instrument") != "EUR_USD" {
+			t.Error("Got ", r.URL.RawQuery)
+		}
+		count, _ := strconv.Atoi(query.Get("count"))
+		count = min(count, maxTradesPageSize)
+		before := last
+		if query.Has("beforeID") {
+			before, _ = strconv.Atoi(query.Get("beforeID"))
+		}
+		// Trade n was opened n days after the start
+		var trades []string
+		for id := before; id >= 1 && len(trades) < count; id-- {
+			trades = append(trades, fmt.Sprintf(`{"id": "%d", "openTime": "%s"}`, id, start.AddDate(0, 0, id).Format(time.RFC3339)))
+		}
+		fmt.Fprintf(w, `{"trades": [%s]}`, strings.Join(trades, ","))
+	}))
+}
+
+func TestTradesIterWalksAllPages(t *testing.T) {
+	var requests []string
+	server := newTradesPagingServer(t, &requests, 7)
+	defer server.Close()
+	client := NewClient(server.URL, "token", server.Client())
+	state := TradeStateFilterClosed
+	instrument := "EUR_USD"
+	count := 3
+	var ids []TradeID
+	for trade, err := range client.TradesIter("001", GetAccountTradesRequest{State: &state, Instrument: &instrument, Count: &count}, time.Time{}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, trade.Id)
+	}
+	if fmt.Sprint(ids) != "[7 6 5 4 3 2 1]" || len(requests) != 3 {
+		t.Error("Got ", ids, requests)
+	}
+}
+
+func TestTradesIterStopsAtDateBoundaryAndLazily(t *testing.T) {
+	var requests []string
+	server := newTradesPagingServer(t, &requests, 7)
+	defer server.Close()
+	client := NewClient(server.URL, "token", server.Client())
+	state := TradeStateFilterClosed
+	instrument := "EUR_USD"
+	count := 2
+	request := GetAccountTradesRequest{State: &state, Instrument: &instrument, Count: &count}
+	var ids []TradeID
+	for trade, err := range client.TradesIter("001", request, time.Date(2024, 1, 4, 0, 0, 0, 0, time.UTC)) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, trade.Id)
+	}
+	if fmt.Sprint(ids) != "[7 6 5 4 3]" {
+		t.Error("Got ", ids)
+	}
+
+	requests = nil
+	for trade := range client.TradesIter("001", request, time.Time{}) {
+		if trade.Id == "6" {
+			break
+		}
+	}
+	if len(requests) != 1 {
+		t.Error("Got ", requests)
+	}
+}
+
+func TestTradesIterClampsPageSize(t *testing.T) {
+	var requests []string
+	server := newTradesPagingServer(t, &requests, 700)
+	defer server.Close()
+	client := NewClient(server.URL, "token", server.Client())
+	state := TradeStateFilterClosed
+	instrument := "EUR_USD"
+	count := 1000
+	trades := 0
+	for _, err := range client.TradesIter("001", GetAccountTradesRequest{State: &state, Instrument: &instrument, Count: &count}, time.Time{}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		trades++
+	}
+	if trades != 700 || len(requests) != 2 || !strings.Contains(requests[0], "count=500") || count != 1000 {
+		t.Error("Got ", trades, requests)
+	}
+}
+
+func TestTradesIterNonPositivePageSize(t *testing.T) {
+	for _, last := range []int{0, 3} {
+		var requests []string
+		server := newTradesPagingServer(t, &requests, last)
+		client := NewClient(server.URL, "token", server.Client())
+		state := TradeStateFilterClosed
+		instrument := "EUR_USD"
+		count := 0
+		trades := 0
+		for _, err := range client.TradesIter("001", GetAccountTradesRequest{State: &state, Instrument: &instrument, Count: &count}, time.Time{}) {
+			if err != nil {
+				t.Fatal(err)
+			}
+			trades++
+		}
+		server.Close()
+		if trades != last || len(requests) != 1 || !strings.Contains(requests[0], "count=500") {
+			t.Error("Got ", trades, requests)
+		}
+	}
+}
@@ -1,12 +1,86 @@
 package oanda_sdk
 
 import (
+	"encoding/json"
+	"fmt"
 	"github.com/shopspring/decimal"
 	"time"
 )
 
 type Transaction interface {
 	GetType() TransactionType
+
+	// GetId returns the Transaction’s Identifier.
+	GetId() TransactionID
+}
+
+// transactionDecoders maps each TransactionType onto a function decoding its concrete representation
+var transactionDecoders = map[TransactionType]func([]byte) (Transaction, error){
+	TransactionTypeCreate:                            decodeTransaction[CreateTransaction],
+	TransactionTypeClose:                             decodeTransaction[CloseTransaction],
+	TransactionTypeReopen:                            decodeTransaction[ReopenTransaction],
+	TransactionTypeClientConfigure:                   decodeTransaction[ClientConfigureTransaction],
+	TransactionTypeClientConfigureReject:             decodeTransaction[ClientConfigureRejectTransaction],
+	TransactionTypeTransferFunds:                     decodeTransaction[TransferFundsTransaction],
+	TransactionTypeTransferFundsReject:               decodeTransaction[TransferFundsRejectTransaction],
+	TransactionTypeMarketOrder:                       decodeTransaction[MarketOrderTransaction],
+	TransactionTypeMarketOrderReject:                 decodeTransaction[MarketOrderRejectTransaction],
+	TransactionTypeFixedPriceOrder:                   decodeTransaction[FixedPriceOrderTransaction],
+	TransactionTypeLimitOrder:                        decodeTransaction[LimitOrderTransaction],
+	TransactionTypeLimitOrderReject:                  decodeTransaction[LimitOrderRejectTransaction],
+	TransactionTypeStopOrder:                         decodeTransaction[StopOrderTransaction],
+	TransactionTypeStopOrderReject:                   decodeTransaction[StopOrderRejectTransaction],
+	TransactionTypeMarketIfTouchedOrder:              decodeTransaction[MarketIfTouchedOrderTransaction],
+	TransactionTypeMarketIfTouchedOrderReject:        decodeTransaction[MarketIfTouchedOrderRejectTransaction],
+	TransactionTypeTakeProfitOrder:                   decodeTransaction[TakeProfitOrderTransaction],
+	TransactionTypeTakeProfitOrderReject:             decodeTransaction[TakeProfitOrderRejectTransaction],
+	TransactionTypeStopLossOrder:                     decodeTransaction[StopLossOrderTransaction],
+	TransactionTypeStopLossOrderReject:               decodeTransaction[StopLossOrderRejectTransaction],
+	TransactionTypeGuaranteedStopLossOrder:           decodeTransaction[GuaranteedStopLossOrderTransaction],
+	TransactionTypeGuaranteedStopLossOrderReject:     decodeTransaction[GuaranteedStopLossOrderRejectTransaction],
+	TransactionTypeTrailingStopLossOrder:             decodeTransaction[TrailingStopLossOrderTransaction],
+	TransactionTypeTrailingStopLossOrderReject:       decodeTransaction[TrailingStopLossOrderRejectTransaction],
+	TransactionTypeOrderFill:                         decodeTransaction[OrderFillTransaction],
+	TransactionTypeOrderCancel:                       decodeTransaction[OrderCancelTransaction],
+	TransactionTypeOrderCancelReject:                 decodeTransaction[OrderCancelRejectTransaction],
+	TransactionTypeOrderClientExtensionsModify:       decodeTransaction[OrderClientExtensionsModifyTransaction],
+	TransactionTypeOrderClientExtensionsModifyReject: decodeTransaction[OrderClientExtensionsModifyRejectTransaction],
+	TransactionTypeTradeClientExtensionsModify:       decodeTransaction[TradeClientExtensionsModifyTransaction],
+	TransactionTypeTradeClientExtensionsModifyReject: decodeTransaction[TradeClientExtensionsModifyRejectTransaction],
+	TransactionTypeMarginCallEnter:                   decodeTransaction[MarginCallEnterTransaction],
+	TransactionTypeMarginCallExtend:                  decodeTransaction[MarginCallExtendTransaction],
+	TransactionTypeMarginCallExit:                    decodeTransaction[MarginCallExitTransaction],
+	TransactionTypeDelayedTradeClosure:               decodeTransaction[DelayedTradeClosureTransaction],
+	TransactionTypeDailyFinancing:                    decodeTransaction[DailyFinancingTransaction],
+	TransactionTypeDividendAdjustment:                decodeTransaction[DividendAdjustmentTransaction],
+	TransactionTypeResetResettablePl:                 decodeTransaction[ResetResettablePLTransaction],
+}
+
+func decodeTransaction[T Transaction](data []byte) (Transaction, error) {
+	var transaction T
+	err := json.Unmarshal(data, &transaction)
+	if err != nil {
+		return nil, err
+	}
+	return transaction, nil
+}
+
+// UnmarshalTransaction decodes a JSON encoded Transaction into its concrete type based on its type field. A JSON null
+// results in a nil Transaction.
+func UnmarshalTransaction(data []byte) (Transaction, error) {
+	if len(data) == 0 || string(data) == "null" {
+		return nil, nil
+	}
+	var base TransactionBase
+	err := json.Unmarshal(data, &base)
+	if err != nil {
+		return nil, err
+	}
+	decoder, ok := transactionDecoders[base.Type]
+	if !ok {
+		return nil, fmt.Errorf("unknown transaction type %q", base.Type)
+	}
+	return decoder(data)
 }
 
 type TransactionBase struct {
@@ -33,6 +107,10 @@ type TransactionBase struct {
 	Type TransactionType `json:"type"`
 }
 
+func (tb TransactionBase) GetId() TransactionID {
+	return tb.Id
+}
+
 // CreateTransaction represents the creation of an Account.
 type CreateTransaction struct {
 	TransactionBase
@@ -142,6 +220,10 @@ type TransferFundsRejectTransaction struct {
 	RejectReason TransactionRejectReason `json:"rejectReason"`
 }
 
+func (tfrt TransferFundsRejectTransaction) GetType() TransactionType {
+	return TransactionTypeTransferFundsReject
+}
+
 // MarketOrderTransaction represents the creation of a MarketOrder in the user's account. A MarketOrder is an Order that
 // is filled immediately at the current market price. MarketOrders can be specialized when they are created to
 // accomplish a specific task: to close a Trade, to closeout a Position or to participate in a Margin closeout
@@ -956,7 +1038,7 @@ type OrderFillTransaction struct {
 	FullVWAP decimal.Decimal `json:"fullVWAP"`
 
 	// The price in effect for the account at the time of the Order fill.
-	FullPrice decimal.Decimal `json:"fullPrice"`
+	FullPrice ClientPrice `json:"fullPrice"`
 
 	// The reason that an Order was filled
 	Reason OrderFillReason `json:"reason"`
@@ -1015,6 +1097,19 @@ func (OrderFillTransaction) GetType() TransactionType {
 	return TransactionTypeOrderFill
 }
 
+// TradeReduceFor returns how the fill reduced the given Trade, whether it closed the Trade or only reduced it
+func (oft OrderFillTransaction) TradeReduceFor(tradeID TradeID) (TradeReduce, bool) {
+	if oft.TradeReduced.TradeID == tradeID {
+		return oft.TradeReduced, true
+	}
+	for _, tradeReduce := range oft.TradesClosed {
+		if tradeReduce.TradeID == tradeID {
+			return tradeReduce, true
+		}
+	}
+	return TradeReduce{}, false
+}
+
 // OrderCancelTransaction represents the cancellation of an Order in the client's Account
 type OrderCancelTransaction struct {
 	TransactionBase
@@ -1029,7 +1124,7 @@ type OrderCancelTransaction struct {
 	Reason OrderCancelReason `json:"reason"`
 
 	// The ID of the Order that replaced this Order (only provided if this Order was cancelled for replacement).
-	ReplacedByOrderID *Order `json:"replacedByOrderID"`
+	ReplacedByOrderID *OrderID `json:"replacedByOrderID"`
 }
 
 func (OrderCancelTransaction) GetType() TransactionType {
@@ -1816,7 +1911,7 @@ type TakeProfitDetails struct {
 	GtdTime *time.Time `json:"gtdTime,omitempty"`
 
 	// The ClientExtensions to add to the TakeProfitOrder when created.
-	ClientExtensions ClientExtensions `json:"clientExtensions"`
+	ClientExtensions *ClientExtensions `json:"clientExtensions,omitempty"`
 }
 
 // StopLossDetails specifies the details of a StopLossOrder to be created on behalf of a client. This may happen when an
@@ -1839,7 +1934,7 @@ type StopLossDetails struct {
 	GtdTime *time.Time `json:"gtdTime,omitempty"`
 
 	// The ClientExtensions to add to the StopLossOrder when created.
-	ClientExtensions ClientExtensions `json:"clientExtensions"`
+	ClientExtensions *ClientExtensions `json:"clientExtensions,omitempty"`
 }
 
 // GuaranteedStopLossDetails specifies the details of a GuaranteedStopLossOrder to be created on behalf of a client.
@@ -1863,7 +1958,7 @@ type GuaranteedStopLossDetails struct {
 	GtdTime *time.Time `json:"gtdTime,omitempty"`
 
 	// The ClientExtensions to add to the GuaranteedStopLossOrder when created.
-	ClientExtensions ClientExtensions `json:"clientExtensions"`
+	ClientExtensions *ClientExtensions `json:"clientExtensions,omitempty"`
 }
 
 // TrailingStopLossDetails specifies the details of a TrailingStopLossOrder to be created on behalf of a client. This may happen when an
@@ -1882,7 +1977,7 @@ type TrailingStopLossDetails struct {
 	GtdTime *time.Time `json:"gtdTime,omitempty"`
 
 	// The ClientExtensions to add to the TrailingStopLossOrder when created.
-	ClientExtensions ClientExtensions `json:"clientExtensions"`
+	ClientExtensions *ClientExtensions `json:"clientExtensions,omitempty"`
 }
 
 // TradeOpen object represents a Trade for an instrument that was opened in an Account. It is found embedded in